@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"db_project/app/models"
+	"db_project/app/usecases"
+	"db_project/utils/errors"
+	"db_project/utils/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type HandlerAPIKeys struct {
+	UseCase usecases.IAPIKeyUseCase
+	Logger  *slog.Logger
+}
+
+func MakeAPIKeysHandler(useCase usecases.IAPIKeyUseCase, logger *slog.Logger) *HandlerAPIKeys {
+	return &HandlerAPIKeys{UseCase: useCase, Logger: logger}
+}
+
+func (handler *HandlerAPIKeys) Create(c *gin.Context) {
+	nickname := c.Param("nickname")
+	if err := validation.Nickname("nickname", nickname); err != nil {
+		c.Error(err)
+		return
+	}
+
+	request := &models.APIKeyRequest{}
+	err := easyjson.UnmarshalFromReader(c.Request.Body, request)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	key, err := handler.UseCase.Create(c.Request.Context(), nickname, request)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, key)
+}
+
+func (handler *HandlerAPIKeys) List(c *gin.Context) {
+	nickname := c.Param("nickname")
+	if err := validation.Nickname("nickname", nickname); err != nil {
+		c.Error(err)
+		return
+	}
+
+	keys, err := handler.UseCase.List(c.Request.Context(), nickname)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (handler *HandlerAPIKeys) Revoke(c *gin.Context) {
+	nickname := c.Param("nickname")
+	if err := validation.Nickname("nickname", nickname); err != nil {
+		c.Error(err)
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = handler.UseCase.Revoke(c.Request.Context(), nickname, id); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"db_project/app/models"
+	"db_project/app/usecases"
+	"db_project/utils/errors"
+	"db_project/utils/validation"
+	"github.com/gin-gonic/gin"
+	"github.com/mailru/easyjson"
+	"log/slog"
+	"net/http"
+)
+
+type HandlerAuth struct {
+	UseCase usecases.IAuthUseCase
+	Logger  *slog.Logger
+}
+
+func MakeAuthHandler(useCase usecases.IAuthUseCase, logger *slog.Logger) *HandlerAuth {
+	return &HandlerAuth{UseCase: useCase, Logger: logger}
+}
+
+func (handler *HandlerAuth) Login(c *gin.Context) {
+	credentials := &models.Credentials{}
+	err := easyjson.UnmarshalFromReader(c.Request.Body, credentials)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(credentials); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pair, err := handler.UseCase.Login(c.Request.Context(), credentials)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, pair)
+}
+
+func (handler *HandlerAuth) Refresh(c *gin.Context) {
+	request := &models.RefreshRequest{}
+	err := easyjson.UnmarshalFromReader(c.Request.Body, request)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	pair, err := handler.UseCase.Refresh(c.Request.Context(), request.RefreshToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, pair)
+}
+
+func (handler *HandlerAuth) Logout(c *gin.Context) {
+	request := &models.RefreshRequest{}
+	err := easyjson.UnmarshalFromReader(c.Request.Body, request)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(request); err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err = handler.UseCase.Logout(c.Request.Context(), request.RefreshToken); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -4,30 +4,32 @@ import (
 	"db_project/app/models"
 	"db_project/app/usecases"
 	"db_project/utils/errors"
-	"db_project/utils/queryCheck"
+	"db_project/utils/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/mailru/easyjson"
+	"log/slog"
 	"net/http"
 )
 
 type HandlerForum struct {
 	UseCase usecases.IForumUseCase
+	Logger  *slog.Logger
 }
 
-func MakeForumsHandler(useCase usecases.IForumUseCase) *HandlerForum {
-	return &HandlerForum{UseCase: useCase}
+func MakeForumsHandler(useCase usecases.IForumUseCase, logger *slog.Logger) *HandlerForum {
+	return &HandlerForum{UseCase: useCase, Logger: logger}
 }
 
 func (handler *HandlerForum) Get(c *gin.Context) {
 	slug := c.Param("slug")
-	if v, _ := queryCheck.GetInstance(); !v.CheckSlug(slug) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug"))
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
 		return
 	}
 
-	forum, err := handler.UseCase.Get(slug)
+	forum, err := handler.UseCase.Get(c.Request.Context(), slug)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -39,23 +41,24 @@ func (handler *HandlerForum) Create(c *gin.Context) {
 
 	err := easyjson.UnmarshalFromReader(c.Request.Body, forum)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
 		return
 	}
 
-	if v, _ := queryCheck.GetInstance(); !v.CheckSlug(forum.Slug) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug"))
+	if err = validation.Struct(forum); err != nil {
+		c.Error(err)
 		return
 	}
 
-	createdForum, err := handler.UseCase.Create(forum)
+	createdForum, err := handler.UseCase.Create(c.Request.Context(), forum)
 
 	if err != nil {
-		if err.(errors.MsgErrors).Code() == errors.ForumAlreadyExists.Code() {
+		if errors.Is(err, errors.ForumAlreadyExists) {
 			c.JSON(errors.ForumAlreadyExists.Code(), createdForum)
 			return
 		}
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -65,23 +68,27 @@ func (handler *HandlerForum) Create(c *gin.Context) {
 func (handler *HandlerForum) GetUsers(c *gin.Context) {
 	var slug string
 	slug = c.Param("slug")
-	if v, _ := queryCheck.GetInstance(); !v.CheckSlug(slug) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug"))
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
 		return
 	}
 
 	params := &models.ForumUserQueryParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректные query params"))
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
 	}
 
-	v, _ := queryCheck.GetInstance()
-	v.CheckForumUserQuery(params)
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
+		return
+	}
 
-	threads, err := handler.UseCase.GetUsers(slug, params)
+	threads, err := handler.UseCase.GetUsers(c.Request.Context(), slug, params)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -93,29 +100,30 @@ func (handler *HandlerForum) CreateThread(c *gin.Context) {
 	thread := &models.Thread{}
 
 	thread.Forum = c.Param("slug")
-	if v, _ := queryCheck.GetInstance(); !v.CheckSlug(thread.Forum) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug forum"))
+	if err := validation.Slug("slug", thread.Forum); err != nil {
+		c.Error(err)
 		return
 	}
 
 	err := easyjson.UnmarshalFromReader(c.Request.Body, thread)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
 		return
 	}
 
-	if v, _ := queryCheck.GetInstance(); thread.Slug != "" && !v.CheckSlug(thread.Slug) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug thread"))
+	if err = validation.Struct(thread); err != nil {
+		c.Error(err)
 		return
 	}
 
-	createdThread, err := handler.UseCase.CreateThread(thread)
+	createdThread, err := handler.UseCase.CreateThread(c.Request.Context(), thread)
 	if err != nil {
-		if err.(errors.MsgErrors).Code() == errors.ThreadAlreadyExists.Code() {
+		if errors.Is(err, errors.ThreadAlreadyExists) {
 			c.JSON(errors.ThreadAlreadyExists.Code(), createdThread)
 			return
 		}
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -125,26 +133,101 @@ func (handler *HandlerForum) CreateThread(c *gin.Context) {
 
 func (handler *HandlerForum) GetThreads(c *gin.Context) {
 	slug := c.Param("slug")
-	if v, _ := queryCheck.GetInstance(); !v.CheckSlug(slug) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректный slug"))
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
 		return
 	}
 
 	params := &models.ForumQueryParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректные query params"))
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
 	}
 
-	v, _ := queryCheck.GetInstance()
-	v.CheckForumQuery(params)
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
+		return
+	}
 
-	threads, err := handler.UseCase.GetThreads(slug, params)
+	threads, err := handler.UseCase.GetThreads(c.Request.Context(), slug, params)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, threads)
 	return
 }
+
+// Update changes the non-empty fields of the body. A slug renames the forum.
+func (handler *HandlerForum) Update(c *gin.Context) {
+	slug := c.Param("slug")
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
+		return
+	}
+
+	forum := &models.ForumUpdate{}
+	err := easyjson.UnmarshalFromReader(c.Request.Body, forum)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Partial(forum); err != nil {
+		c.Error(err)
+		return
+	}
+
+	updatedForum, err := handler.UseCase.Update(c.Request.Context(), slug, forum)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedForum)
+}
+
+// Delete removes a forum with its threads, posts and votes and reports what
+// was deleted.
+func (handler *HandlerForum) Delete(c *gin.Context) {
+	slug := c.Param("slug")
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
+		return
+	}
+
+	report, err := handler.UseCase.Delete(c.Request.Context(), slug)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (handler *HandlerForum) List(c *gin.Context) {
+	params := &models.ForumListQueryParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
+		return
+	}
+
+	list, err := handler.UseCase.List(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
@@ -4,8 +4,10 @@ import (
 	"db_project/app/models"
 	"db_project/app/usecases"
 	"db_project/utils/errors"
+	"db_project/utils/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/mailru/easyjson"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -13,16 +15,17 @@ import (
 
 type HandlerPosts struct {
 	UseCase usecases.IPostUseCase
+	Logger  *slog.Logger
 }
 
-func MakePostsHandler(useCase usecases.IPostUseCase) *HandlerPosts {
-	return &HandlerPosts{UseCase: useCase}
+func MakePostsHandler(useCase usecases.IPostUseCase, logger *slog.Logger) *HandlerPosts {
+	return &HandlerPosts{UseCase: useCase, Logger: logger}
 }
 
 func (handler *HandlerPosts) Get(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		c.Error(errors.BadRequest)
 		return
 	}
 
@@ -32,9 +35,9 @@ func (handler *HandlerPosts) Get(c *gin.Context) {
 		details = strings.Split(detailsRaw, ",")
 	}
 
-	post, err := handler.UseCase.Get(int(id), details)
+	post, err := handler.UseCase.Get(c.Request.Context(), int(id), details)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -45,25 +48,73 @@ func (handler *HandlerPosts) Get(c *gin.Context) {
 func (handler *HandlerPosts) Update(c *gin.Context) {
 	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		c.Error(errors.BadRequest)
 		return
 	}
 
 	post := &models.Post{}
 	err = easyjson.UnmarshalFromReader(c.Request.Body, post)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Partial(post); err != nil {
+		c.Error(err)
 		return
 	}
 
 	post.ID = int(id)
 
-	forum, err := handler.UseCase.Update(post)
+	forum, err := handler.UseCase.Update(c.Request.Context(), post)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, forum)
 	return
 }
+
+// Delete hides a post, or removes it for good with ?hard=true.
+func (handler *HandlerPosts) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.BadRequest)
+		return
+	}
+	hard, err := strconv.ParseBool(c.DefaultQuery("hard", "false"))
+	if err != nil {
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+
+	post, err := handler.UseCase.Delete(c.Request.Context(), int(id), hard)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if hard {
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.JSON(http.StatusOK, post)
+}
+
+func (handler *HandlerPosts) Restore(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	post, err := handler.UseCase.Restore(c.Request.Context(), int(id))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"db_project/app/usecases"
+	"db_project/utils/validation"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"net/http"
+)
+
+type HandlerRoles struct {
+	UseCase usecases.IRoleUseCase
+	Logger  *slog.Logger
+}
+
+func MakeRolesHandler(useCase usecases.IRoleUseCase, logger *slog.Logger) *HandlerRoles {
+	return &HandlerRoles{UseCase: useCase, Logger: logger}
+}
+
+func (handler *HandlerRoles) Moderators(c *gin.Context) {
+	slug := c.Param("slug")
+	if err := validation.Slug("slug", slug); err != nil {
+		c.Error(err)
+		return
+	}
+
+	moderators, err := handler.UseCase.Moderators(c.Request.Context(), slug)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderators)
+}
+
+func (handler *HandlerRoles) GrantModerator(c *gin.Context) {
+	slug, nickname, err := moderatorParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	moderator, err := handler.UseCase.GrantModerator(c.Request.Context(), slug, nickname)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, moderator)
+}
+
+func (handler *HandlerRoles) RevokeModerator(c *gin.Context) {
+	slug, nickname, err := moderatorParams(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err = handler.UseCase.RevokeModerator(c.Request.Context(), slug, nickname); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func moderatorParams(c *gin.Context) (slug string, nickname string, err error) {
+	slug = c.Param("slug")
+	if err = validation.Slug("slug", slug); err != nil {
+		return
+	}
+	nickname = c.Param("nickname")
+	err = validation.Nickname("nickname", nickname)
+	return
+}
@@ -1,36 +1,105 @@
 package handlers
 
 import (
+	"db_project/app/models"
 	"db_project/app/usecases"
+	"db_project/utils/constants"
 	"db_project/utils/errors"
+	"db_project/utils/health"
+	"db_project/utils/validation"
 	"github.com/gin-gonic/gin"
+	"log/slog"
 	"net/http"
 )
 
 type HandlerServices struct {
-	UseCase usecases.IServiceUseCase
+	UseCase   usecases.IServiceUseCase
+	Readiness *health.Readiness
+	Logger    *slog.Logger
 }
 
-func MakeServicesHandler(useCase usecases.IServiceUseCase) *HandlerServices {
-	return &HandlerServices{UseCase: useCase}
+func MakeServicesHandler(useCase usecases.IServiceUseCase, readiness *health.Readiness, logger *slog.Logger) *HandlerServices {
+	return &HandlerServices{UseCase: useCase, Readiness: readiness, Logger: logger}
 }
 
 func (handler *HandlerServices) Clear(c *gin.Context) {
-	err := handler.UseCase.Clear()
+	params := &models.ClearQueryParams{}
+	err := c.ShouldBindQuery(params)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
 		return
 	}
 
-	c.Status(http.StatusOK)
+	report, err := handler.UseCase.Clear(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ClearDisabled answers the clear endpoint when admin.clear_enabled is off.
+func (handler *HandlerServices) ClearDisabled(c *gin.Context) {
+	c.Error(errors.ClearDisabled)
 }
 
 func (handler *HandlerServices) Status(c *gin.Context) {
-	status, err := handler.UseCase.Status()
+	params := &models.StatusQueryParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+
+	status, err := handler.UseCase.Status(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (handler *HandlerServices) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, &models.HealthStatus{Status: "alive"})
+}
+
+func (handler *HandlerServices) Ready(c *gin.Context) {
+	if !handler.Readiness.Ready() {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, &models.HealthStatus{Status: "not ready", Reason: "not serving"})
+		return
+	}
+
+	status, err := handler.UseCase.Ready(c.Request.Context())
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
+		return
+	}
+	if status.Reason != "" {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, status)
 		return
 	}
 
 	c.JSON(http.StatusOK, status)
 }
+
+func (handler *HandlerServices) Deep(c *gin.Context) {
+	report, err := handler.UseCase.Health(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if report.Status == constants.HealthUnavailable {
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -4,26 +4,30 @@ import (
 	"db_project/app/models"
 	"db_project/app/usecases"
 	"db_project/utils/errors"
-	"db_project/utils/queryCheck"
+	"db_project/utils/ratelimit"
+	"db_project/utils/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/mailru/easyjson"
+	"log/slog"
 	"net/http"
+	"strconv"
 )
 
 type HandlerThreads struct {
 	UseCase usecases.IThreadUseCase
+	Logger  *slog.Logger
 }
 
-func MakeThreadsHandler(useCase usecases.IThreadUseCase) *HandlerThreads {
-	return &HandlerThreads{UseCase: useCase}
+func MakeThreadsHandler(useCase usecases.IThreadUseCase, logger *slog.Logger) *HandlerThreads {
+	return &HandlerThreads{UseCase: useCase, Logger: logger}
 }
 
 func (handler *HandlerThreads) Get(c *gin.Context) {
 	slugOrId := c.Param("slug_or_id")
 
-	forum, err := handler.UseCase.Get(slugOrId)
+	forum, err := handler.UseCase.Get(c.Request.Context(), slugOrId)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -37,13 +41,19 @@ func (handler *HandlerThreads) Update(c *gin.Context) {
 	thread := &models.Thread{}
 	err := easyjson.UnmarshalFromReader(c.Request.Body, thread)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
 		return
 	}
 
-	forum, err := handler.UseCase.Update(slugOrId, thread)
+	if err = validation.Partial(thread); err != nil {
+		c.Error(err)
+		return
+	}
+
+	forum, err := handler.UseCase.Update(c.Request.Context(), slugOrId, thread)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -57,13 +67,19 @@ func (handler *HandlerThreads) Vote(c *gin.Context) {
 	vote := &models.Vote{}
 	err := easyjson.UnmarshalFromReader(c.Request.Body, vote)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(vote); err != nil {
+		c.Error(err)
 		return
 	}
 
-	forum, err := handler.UseCase.Vote(slugOrId, vote)
+	forum, err := handler.UseCase.Vote(c.Request.Context(), slugOrId, vote)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -78,13 +94,25 @@ func (handler *HandlerThreads) PostsCreate(c *gin.Context) {
 	err := easyjson.UnmarshalFromReader(c.Request.Body, &posts)
 
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(posts); err != nil {
+		c.Error(err)
+		return
+	}
+
+	// The rate limiter took one write for the request, a batch costs one per post.
+	if err = ratelimit.Charge(c.Request.Context(), len(posts)-1); err != nil {
+		c.Error(err)
 		return
 	}
 
-	createdPosts, err := handler.UseCase.CreatePosts(slugOrId, posts)
+	createdPosts, err := handler.UseCase.CreatePosts(c.Request.Context(), slugOrId, posts)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -98,19 +126,66 @@ func (handler *HandlerThreads) GetPosts(c *gin.Context) {
 	params := &models.PostsQueryParams{}
 	err := c.ShouldBindQuery(params)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректные query params"))
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
 	}
 
-	if v, _ := queryCheck.GetInstance(); !v.CheckPostsQuery(params) {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest.SetTextDetails("Не корректные query params"))
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
+		return
 	}
 
-	createdPosts, err := handler.UseCase.GetPosts(slugOrId, params)
+	createdPosts, err := handler.UseCase.GetPosts(c.Request.Context(), slugOrId, params)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, createdPosts)
 	return
 }
+
+// Delete hides a thread, or removes it for good with ?hard=true.
+func (handler *HandlerThreads) Delete(c *gin.Context) {
+	hard, err := strconv.ParseBool(c.DefaultQuery("hard", "false"))
+	if err != nil {
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+
+	if err = handler.UseCase.Delete(c.Request.Context(), c.Param("slug_or_id"), hard); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (handler *HandlerThreads) Restore(c *gin.Context) {
+	thread, err := handler.UseCase.Restore(c.Request.Context(), c.Param("slug_or_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
+
+func (handler *HandlerThreads) Archive(c *gin.Context) {
+	handler.archive(c, true)
+}
+
+func (handler *HandlerThreads) Unarchive(c *gin.Context) {
+	handler.archive(c, false)
+}
+
+func (handler *HandlerThreads) archive(c *gin.Context, archived bool) {
+	thread, err := handler.UseCase.Archive(c.Request.Context(), c.Param("slug_or_id"), archived)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, thread)
+}
@@ -4,24 +4,32 @@ import (
 	"db_project/app/models"
 	"db_project/app/usecases"
 	"db_project/utils/errors"
+	"db_project/utils/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/mailru/easyjson"
+	"log/slog"
 	"net/http"
 )
 
 type HandlerUsers struct {
 	UseCase usecases.IUserUseCase
+	Logger  *slog.Logger
 }
 
-func MakeUsersHandler(useCase usecases.IUserUseCase) *HandlerUsers {
-	return &HandlerUsers{UseCase: useCase}
+func MakeUsersHandler(useCase usecases.IUserUseCase, logger *slog.Logger) *HandlerUsers {
+	return &HandlerUsers{UseCase: useCase, Logger: logger}
 }
 
 func (handler *HandlerUsers) Get(c *gin.Context) {
 	nickname := c.Param("nickname")
-	model, err := handler.UseCase.Get(&nickname)
+	if err := validation.Nickname("nickname", nickname); err != nil {
+		c.Error(err)
+		return
+	}
+
+	model, err := handler.UseCase.Get(c.Request.Context(), &nickname)
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 	c.JSON(http.StatusOK, model)
@@ -32,18 +40,24 @@ func (handler *HandlerUsers) Create(c *gin.Context) {
 	model.Username = c.Param("nickname")
 	err := easyjson.UnmarshalFromReader(c.Request.Body, model)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
+		return
+	}
+
+	if err = validation.Struct(model); err != nil {
+		c.Error(err)
 		return
 	}
 
-	users, err := handler.UseCase.Create(model)
-	if users != nil {
-		c.JSON(err.(errors.MsgErrors).Code(), users)
+	users, err := handler.UseCase.Create(c.Request.Context(), model)
+	if errors.Is(err, errors.ConflictUserCreate) {
+		c.JSON(errors.ConflictUserCreate.Code(), users)
 		return
 	}
 
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
@@ -55,16 +69,45 @@ func (handler *HandlerUsers) Update(c *gin.Context) {
 	model.Username = c.Param("nickname")
 	err := easyjson.UnmarshalFromReader(c.Request.Body, model)
 	if err != nil {
-		c.AbortWithStatusJSON(errors.BadRequest.Code(), errors.BadRequest)
+		handler.Logger.DebugContext(c.Request.Context(), "can't decode request body", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest)
 		return
 	}
 
-	user, err := handler.UseCase.Update(model)
+	if err = validation.Partial(model); err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, err := handler.UseCase.Update(c.Request.Context(), model)
 
 	if err != nil {
-		c.AbortWithStatusJSON(err.(errors.MsgErrors).Code(), err)
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, user)
 }
+
+func (handler *HandlerUsers) All(c *gin.Context) {
+	params := &models.UserListQueryParams{}
+	err := c.ShouldBindQuery(params)
+	if err != nil {
+		handler.Logger.DebugContext(c.Request.Context(), "can't bind query parameters", slog.String("error", err.Error()))
+		c.Error(errors.BadRequest.WithDetails("invalid_query"))
+		return
+	}
+
+	if err = validation.Struct(params); err != nil {
+		c.Error(err)
+		return
+	}
+
+	users, err := handler.UseCase.All(c.Request.Context(), params)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, users)
+}
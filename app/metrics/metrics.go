@@ -0,0 +1,117 @@
+// Package metrics collects the Prometheus metrics of the service: HTTP
+// requests per route, query latency and errors per named repository query,
+// and the state of the connection pool.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "forum"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	queryDuration *prometheus.HistogramVec
+	queryErrors   *prometheus.CounterVec
+}
+
+func CreateMetrics() *Metrics {
+	metrics := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_duration_seconds",
+			Help:      "Query latency by repository query, e.g. ThreadQuery.VoteBySlug or DescSincePostQuery.tree.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"query"}),
+		queryErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "db",
+			Name:      "query_errors_total",
+			Help:      "Failed queries by repository query and SQLSTATE.",
+		}, []string{"query", "sqlstate"}),
+	}
+
+	metrics.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		metrics.httpRequests,
+		metrics.httpDuration,
+		metrics.queryDuration,
+		metrics.queryErrors,
+	)
+
+	return metrics
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveRequest(method string, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// RegisterPool exports the pgxpool statistics of pool.
+func (m *Metrics) RegisterPool(pool *pgxpool.Pool) {
+	m.registry.MustRegister(&poolCollector{pool: pool})
+}
+
+type queryStartKey struct{}
+
+func (m *Metrics) QueryStart(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, time.Now())
+}
+
+func (m *Metrics) QueryEnd(ctx context.Context, name string, err error) {
+	if start, ok := ctx.Value(queryStartKey{}).(time.Time); ok {
+		m.queryDuration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+	}
+	if err != nil {
+		m.queryErrors.WithLabelValues(name, SQLState(err)).Inc()
+	}
+}
+
+// SQLState returns the SQLSTATE of a PostgreSQL error, "timeout" or
+// "canceled" for errors caused by the request context and "other" otherwise.
+func SQLState(err error) string {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr):
+		return pgErr.SQLState()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "other"
+	}
+}
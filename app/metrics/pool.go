@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	poolAcquiredDesc = prometheus.NewDesc(namespace+"_pgxpool_acquired_conns",
+		"Connections currently in use.", nil, nil)
+	poolIdleDesc = prometheus.NewDesc(namespace+"_pgxpool_idle_conns",
+		"Connections currently idle.", nil, nil)
+	poolConstructingDesc = prometheus.NewDesc(namespace+"_pgxpool_constructing_conns",
+		"Connections currently being established.", nil, nil)
+	poolTotalDesc = prometheus.NewDesc(namespace+"_pgxpool_total_conns",
+		"Connections currently open or being established.", nil, nil)
+	poolMaxDesc = prometheus.NewDesc(namespace+"_pgxpool_max_conns",
+		"Configured maximum number of connections.", nil, nil)
+	poolAcquiresDesc = prometheus.NewDesc(namespace+"_pgxpool_acquires_total",
+		"Successful connection acquires.", nil, nil)
+	poolWaitedAcquiresDesc = prometheus.NewDesc(namespace+"_pgxpool_waited_acquires_total",
+		"Acquires that had to wait for a connection because none was idle. "+
+			"A growing rate means requests are queueing for the pool.", nil, nil)
+	poolCanceledAcquiresDesc = prometheus.NewDesc(namespace+"_pgxpool_canceled_acquires_total",
+		"Acquires cancelled by their context while waiting.", nil, nil)
+	poolAcquireSecondsDesc = prometheus.NewDesc(namespace+"_pgxpool_acquire_seconds_total",
+		"Total time spent waiting for connections.", nil, nil)
+)
+
+// poolCollector reads pgxpool.Stat on every scrape.
+type poolCollector struct {
+	pool *pgxpool.Pool
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolAcquiredDesc
+	ch <- poolIdleDesc
+	ch <- poolConstructingDesc
+	ch <- poolTotalDesc
+	ch <- poolMaxDesc
+	ch <- poolAcquiresDesc
+	ch <- poolWaitedAcquiresDesc
+	ch <- poolCanceledAcquiresDesc
+	ch <- poolAcquireSecondsDesc
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(poolAcquiredDesc, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(poolIdleDesc, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(poolConstructingDesc, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(poolTotalDesc, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(poolMaxDesc, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(poolAcquiresDesc, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolWaitedAcquiresDesc, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolCanceledAcquiresDesc, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(poolAcquireSecondsDesc, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"context"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// KeyVerifier resolves API keys, see usecases.IAPIKeyUseCase.
+type KeyVerifier interface {
+	Verify(ctx context.Context, key string) (apiKey *auth.APIKey, err error)
+}
+
+// Authenticate resolves the credentials of a request and stores the user in
+// the request context (see auth.User). It accepts access tokens as
+// "Authorization: Bearer <token>" and API keys as "Authorization: ApiKey <key>".
+// Requests with invalid credentials are rejected, requests without any go on
+// anonymously. Read-only keys are rejected for anything but reads.
+func Authenticate(tokens *auth.Tokens, keys KeyVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		scheme, credentials, _ := strings.Cut(header, " ")
+		credentials = strings.TrimSpace(credentials)
+		switch {
+		case strings.EqualFold(scheme, "Bearer"):
+			nickname, err := tokens.Parse(credentials)
+			if err != nil {
+				unauthorized(c, errors.InvalidToken)
+				return
+			}
+			ctx = auth.WithUser(ctx, nickname)
+
+		case strings.EqualFold(scheme, "ApiKey"):
+			apiKey, err := keys.Verify(ctx, credentials)
+			if errors.Is(err, errors.InvalidToken) {
+				unauthorized(c, errors.InvalidToken)
+				return
+			}
+			if err != nil {
+				c.Error(err)
+				c.Abort()
+				return
+			}
+			if apiKey.ReadOnly && !readRequest(c.Request.Method) {
+				c.Error(errors.APIKeyReadOnly)
+				c.Abort()
+				return
+			}
+			ctx = auth.WithAPIKey(ctx, apiKey)
+			trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("api_key.id", apiKey.ID))
+
+		default:
+			unauthorized(c, errors.InvalidToken)
+			return
+		}
+
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", auth.User(ctx)))
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+// RequireUser rejects anonymous requests when required is set.
+func RequireUser(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && auth.User(c.Request.Context()) == "" {
+			unauthorized(c, errors.AuthRequired)
+			return
+		}
+		c.Next()
+	}
+}
+
+func unauthorized(c *gin.Context, err *errors.Error) {
+	c.Header("WWW-Authenticate", `Bearer realm="forum", ApiKey realm="forum"`)
+	c.Error(err)
+	c.Abort()
+}
+
+// clientID identifies the client of a request: by API key, by user or, for
+// anonymous requests, by IP. The IP only comes from X-Forwarded-For when the
+// connection is from one of the server.trusted_proxies.
+func clientID(c *gin.Context) string {
+	ctx := c.Request.Context()
+	if key := auth.Key(ctx); key != nil {
+		return "key:" + strconv.FormatInt(key.ID, 10)
+	}
+	if user := auth.User(ctx); user != "" {
+		return "user:" + user
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func readRequest(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
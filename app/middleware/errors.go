@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"db_project/app/models"
+	"db_project/utils/errors"
+	"db_project/utils/i18n"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"strings"
+)
+
+const ProblemContentType = "application/problem+json"
+
+type ErrorOptions struct {
+	// Problem answers with problem details even to clients not asking for them.
+	Problem bool
+	// TypeBase is prefixed to the error key to build the problem type URI.
+	TypeBase string
+	// Catalog translates messages into the language asked for by
+	// Accept-Language. Without it the messages of utils/errors are used.
+	Catalog *i18n.Catalog
+}
+
+// Errors renders the last error attached with c.Error once the handlers are
+// done, unless a response has been written already. Errors other than
+// *errors.Error are logged and answered with 500. The body is RFC 7807
+// problem details when the client accepts application/problem+json or
+// options.Problem is set, and {"code", "message", "message_details"} otherwise.
+// Messages and details are translated through options.Catalog.
+func Errors(log *slog.Logger, options ErrorOptions) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		var apiErr *errors.Error
+		if !errors.As(err, &apiErr) {
+			log.ErrorContext(c.Request.Context(), "unexpected error", slog.String("error", err.Error()))
+			apiErr = errors.ServerInternal.Wrap(err)
+		}
+
+		message, details := apiErr.Message(), apiErr.Details()
+		var fields []models.FieldError
+		for _, field := range apiErr.Fields() {
+			fields = append(fields, models.FieldError{Field: field.Field, Rule: field.Rule, Message: field.Rule})
+		}
+		if options.Catalog != nil {
+			localizer := options.Catalog.Localizer(c.GetHeader("Accept-Language"))
+			if text, ok := localizer.Error(apiErr.Key()); ok {
+				message = text
+			}
+			if text, ok := localizer.Details(details); ok {
+				details = text
+			}
+			for i, field := range apiErr.Fields() {
+				fields[i].Message = localizer.Field(field.Rule, field.Param)
+			}
+			c.Header("Content-Language", localizer.Language())
+		}
+
+		if options.Problem || strings.Contains(c.GetHeader("Accept"), ProblemContentType) {
+			c.Header("Content-Type", ProblemContentType)
+			c.JSON(apiErr.Code(), &models.Problem{
+				Type:     options.TypeBase + apiErr.Key(),
+				Title:    message,
+				Status:   apiErr.Code(),
+				Detail:   details,
+				Instance: c.Request.URL.RequestURI(),
+				Code:     apiErr.Key(),
+				Errors:   fields,
+			})
+			return
+		}
+
+		c.JSON(apiErr.Code(), &models.Message{Code: apiErr.Key(), Msg: message, TextDetails: details, Fields: fields})
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"db_project/utils/errors"
+	"db_project/utils/idempotency"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+const idempotencySweepInterval = time.Minute
+
+// Idempotency makes the requests sent with an Idempotency-Key header safe to
+// retry: the response to the first request under a key is kept for ttl and
+// replayed, with Idempotent-Replayed: true, to later requests with the same
+// key and body. Keys are per client (see clientID), reusing one for another
+// request is answered with 422, retrying while the first request still runs
+// with 409 until the lease of the first one expires. Errors rendered by the
+// Errors middleware and 5xx responses are not kept, so such requests can be
+// retried. It must run after Authenticate.
+func Idempotency(store idempotency.Store, ttl time.Duration, lease time.Duration, logger *slog.Logger) gin.HandlerFunc {
+	var lastSweep atomic.Int64
+	lastSweep.Store(time.Now().UnixNano())
+
+	sweep := func(now time.Time) {
+		last := lastSweep.Load()
+		if now.Sub(time.Unix(0, last)) < idempotencySweepInterval || !lastSweep.CompareAndSwap(last, now.UnixNano()) {
+			return
+		}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), idempotencySweepInterval)
+			defer cancel()
+			if err := store.Forget(ctx, now.Add(-ttl)); err != nil {
+				logger.WarnContext(ctx, "failed to forget idempotency keys", slog.String("error", err.Error()))
+			}
+		}()
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotency.Header)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !idempotency.ValidKey(key) {
+			c.Error(errors.BadRequest.WithDetails("idempotency_key"))
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.Error(errors.BadRequest)
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		now := time.Now()
+		sweep(now)
+
+		scope := clientID(c)
+		fingerprint := idempotency.Fingerprint(c.Request.Method, c.Request.URL.Path, body)
+		claimed, stored, err := store.Claim(ctx, scope, key, fingerprint, now.Add(-ttl), lease)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if !claimed {
+			switch {
+			case stored != nil && stored.Fingerprint != fingerprint:
+				c.Error(errors.IdempotencyMismatch)
+			case stored == nil || stored.Status == 0:
+				c.Error(errors.IdempotencyInProgress)
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(stored.Status, stored.ContentType, stored.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+
+			ctx := context.WithoutCancel(ctx)
+			status := writer.Status()
+			if writer.Written() && status < http.StatusInternalServerError {
+				err = store.Complete(ctx, scope, key, &idempotency.Response{
+					Status:      status,
+					ContentType: writer.Header().Get("Content-Type"),
+					Body:        writer.body.Bytes(),
+				})
+			} else {
+				err = store.Release(ctx, scope, key)
+			}
+			if err != nil {
+				logger.WarnContext(ctx, "failed to store idempotent response",
+					slog.String("key", key), slog.String("error", err.Error()))
+			}
+		}()
+		c.Next()
+	}
+}
+
+// recordingWriter keeps a copy of the body it writes.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"db_project/utils/auth"
+	"db_project/utils/logger"
+	"encoding/hex"
+	"github.com/gin-gonic/gin"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// RequestID takes the request ID from the X-Request-ID header or generates
+// one, echoes it in the response and attaches it to the request context so
+// every log line of the request carries it.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
+		c.Next()
+	}
+}
+
+// AccessLog writes one record per request once it has been handled. Records
+// of authenticated requests name the user and the API key, if any.
+func AccessLog(log *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		ctx := c.Request.Context()
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("client_ip", c.ClientIP()),
+			slog.Int("size", c.Writer.Size()),
+		}
+		if user := auth.User(ctx); user != "" {
+			attrs = append(attrs, slog.String("user", user))
+		}
+		if key := auth.Key(ctx); key != nil {
+			attrs = append(attrs, slog.Int64("api_key", key.ID))
+		}
+		log.LogAttrs(ctx, level, "request", attrs...)
+	}
+}
+
+// Recovery turns a panic into a 500 response and logs it with its stack.
+func Recovery(log *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		log.ErrorContext(c.Request.Context(), "panic while handling request",
+			slog.Any("panic", recovered),
+			slog.String("stack", string(debug.Stack())))
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
+
+func validRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		if r < '!' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"db_project/app/metrics"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Metrics records count, latency and status of every request by route pattern.
+func Metrics(m *metrics.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		m.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
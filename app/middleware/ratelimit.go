@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"context"
+	"db_project/utils/errors"
+	"db_project/utils/ratelimit"
+	"github.com/gin-gonic/gin"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RateLimit throttles clients: requests with an API key by key, other
+// authenticated requests by user and anonymous ones by IP. routes assigns
+// routes to budgets and is keyed like the route timeouts, e.g.
+// "POST /api/thread/:slug_or_id/vote"; other reads use the "read" budget and
+// other writes the "write" one. It must run after Authenticate.
+//
+// The state of the bucket is reported in the RateLimit-Limit, -Remaining and
+// -Reset headers, refusals get 429 and Retry-After. When the store fails,
+// requests are let through.
+func RateLimit(limiter *ratelimit.Limiter, routes map[string]string, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		budget, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			budget = "write"
+			if readRequest(c.Request.Method) {
+				budget = "read"
+			}
+		}
+		request := limiter.Request(budget, clientID(c))
+
+		take := func(ctx context.Context, cost int) error {
+			result, err := request.Take(ctx, cost)
+			if err != nil {
+				logger.WarnContext(ctx, "rate limiter unavailable, request let through",
+					slog.String("budget", budget), slog.String("error", err.Error()))
+				return nil
+			}
+
+			c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+			if !result.Allowed {
+				c.Header("Retry-After", ceilSeconds(max(result.RetryAfter, time.Second)))
+				return errors.RateLimited
+			}
+			return nil
+		}
+
+		ctx := c.Request.Context()
+		if err := take(ctx, 1); err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		c.Request = c.Request.WithContext(ratelimit.WithCharger(ctx, take))
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
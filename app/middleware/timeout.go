@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"time"
+)
+
+// Timeout puts a deadline on the request context so that queries started by
+// the request are cancelled once it passes. routes overrides defaultTimeout
+// per route and is keyed by method and route pattern, e.g.
+// "GET /api/thread/:slug_or_id/posts". A non-positive timeout disables the deadline.
+func Timeout(defaultTimeout time.Duration, routes map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = defaultTimeout
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
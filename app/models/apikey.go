@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// APIKey describes a key of a user. Key is only set in the response that
+// creates it and can't be retrieved later.
+type APIKey struct {
+	ID       int64      `json:"id"`
+	Name     string     `json:"name"`
+	Prefix   string     `json:"prefix"`
+	Scopes   []string   `json:"scopes"`
+	Forum    string     `json:"forum,omitempty"`
+	Created  time.Time  `json:"created"`
+	LastUsed *time.Time `json:"last_used,omitempty"`
+	Key      string     `json:"key,omitempty"`
+}
+
+type APIKeyRequest struct {
+	Name string `json:"name" validate:"required,max=64"`
+	// Scopes are read and write, write includes read. No scopes means write.
+	Scopes []string `json:"scopes" validate:"max=2,dive,oneof=read write"`
+	// Forum restricts writes to one forum.
+	Forum string `json:"forum" validate:"omitempty,slug,max=128"`
+}
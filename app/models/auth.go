@@ -0,0 +1,19 @@
+package models
+
+type Credentials struct {
+	Username string `json:"nickname" validate:"required,nickname,max=64"`
+	Password string `json:"password" validate:"required,max=72"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required,max=128"`
+}
+
+// TokenPair is returned by login and refresh. ExpiresIn is the lifetime of
+// the access token in seconds.
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
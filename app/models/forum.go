@@ -1,24 +1,57 @@
 package models
 
-import "time"
+import (
+	"db_project/utils/constants"
+	"time"
+)
 
 type ForumQueryParams struct {
-	Limit int       `form:"limit"`
+	Limit int       `form:"limit,default=100" validate:"min=1,max=10000"`
 	Since time.Time `form:"since"`
 	Desc  bool      `form:"desc"`
+	// Archived lists archived threads too.
+	Archived bool `form:"archived"`
 }
 
 type Forum struct {
-	ID      int    `json:"id"`
-	Slug    string `json:"slug"`
-	Title   string `json:"title"`
-	User    string `json:"user"`
-	Posts   int    `json:"posts"`
-	Threads int    `json:"threads"`
+	ID          int       `json:"id"`
+	Slug        string    `json:"slug" validate:"required,slug,max=128"`
+	Title       string    `json:"title" validate:"required,max=256"`
+	User        string    `json:"user" validate:"required,nickname,max=64"`
+	Posts       int       `json:"posts"`
+	Threads     int       `json:"threads"`
+	Description string    `json:"description,omitempty" validate:"max=4096"`
+	Created     time.Time `json:"created"`
+}
+
+// ForumUpdate is the body of a forum update. Empty fields keep their value,
+// except Description, which only a missing or null value keeps: "" clears it.
+type ForumUpdate struct {
+	Slug        string  `json:"slug" validate:"required,slug,max=128"`
+	Title       string  `json:"title" validate:"required,max=256"`
+	User        string  `json:"user" validate:"required,nickname,max=64"`
+	Description *string `json:"description" validate:"omitempty,max=4096"`
+}
+
+type ForumListQueryParams struct {
+	Limit int                `form:"limit,default=100" validate:"min=1,max=1000"`
+	Sort  constants.SortType `form:"sort,default=title" validate:"oneof=title created threads posts"`
+	Desc  bool               `form:"desc"`
+	// Query keeps the forums whose title or slug contains it.
+	Query string `form:"q" validate:"max=256"`
+	// Cursor is the Next of the previous page.
+	Cursor string `form:"cursor" validate:"max=1024"`
+}
+
+//easyjson:json
+type ForumList struct {
+	Forums []*Forum `json:"forums"`
+	// Next fetches the following page, it is empty on the last one.
+	Next string `json:"next,omitempty"`
 }
 
 type ForumUserQueryParams struct {
-	Limit int    `form:"limit"`
-	Since string `form:"since"`
+	Limit int    `form:"limit,default=100" validate:"min=1,max=10000"`
+	Since string `form:"since" validate:"omitempty,nickname,max=64"`
 	Desc  bool   `form:"desc"`
 }
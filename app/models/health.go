@@ -0,0 +1,40 @@
+package models
+
+type HealthStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthReport is the result of the deep health check.
+type HealthReport struct {
+	Status     string           `json:"status"`
+	Database   DatabaseHealth   `json:"database"`
+	Pool       PoolHealth       `json:"pool"`
+	Migrations MigrationsHealth `json:"migrations"`
+	Triggers   []TriggerHealth  `json:"triggers"`
+}
+
+type DatabaseHealth struct {
+	Reachable bool    `json:"reachable"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type PoolHealth struct {
+	Acquired int32 `json:"acquired"`
+	Idle     int32 `json:"idle"`
+	Total    int32 `json:"total"`
+	Max      int32 `json:"max"`
+	// Saturation is the share of the maximum pool size currently acquired.
+	Saturation float64 `json:"saturation"`
+}
+
+type MigrationsHealth struct {
+	Current bool   `json:"current"`
+	Error   string `json:"error,omitempty"`
+}
+
+type TriggerHealth struct {
+	Name    string `json:"name"`
+	Present bool   `json:"present"`
+}
@@ -1,20 +1,29 @@
 package models
 
+// Message is the body of an error response.
 type Message struct {
-	ErrorCode   int    `json:"-"`
-	Msg         string `json:"message"`
-	TextDetails string `json:"message_details,omitempty"`
+	Code        string       `json:"code"`
+	Msg         string       `json:"message"`
+	TextDetails string       `json:"message_details,omitempty"`
+	Fields      []FieldError `json:"fields,omitempty"`
 }
 
-func (msg *Message) Error() string {
-	return msg.Msg + " " + msg.TextDetails
+// FieldError is a request field that broke a validation rule.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
-func (msg *Message) Code() int {
-	return msg.ErrorCode
-}
-
-func (msg *Message) SetTextDetails(text string) *Message {
-	msg.TextDetails = text
-	return msg
+// Problem is an RFC 7807 problem details body. Code is the stable error key,
+// e.g. "thread_not_found".
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	Code     string `json:"code"`
+	// Errors lists the invalid request fields.
+	Errors []FieldError `json:"errors,omitempty"`
 }
@@ -8,6 +8,7 @@ import (
 	easyjson "github.com/mailru/easyjson"
 	jlexer "github.com/mailru/easyjson/jlexer"
 	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
 )
 
 // suppress unused package warning
@@ -91,7 +92,7 @@ func (v *Vote) UnmarshalJSON(data []byte) error {
 func (v *Vote) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels1(in *jlexer.Lexer, out *User) {
+func easyjsonD2b7633eDecodeDbProjectAppModels1(in *jlexer.Lexer, out *UserListQueryParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -110,14 +111,20 @@ func easyjsonD2b7633eDecodeDbProjectAppModels1(in *jlexer.Lexer, out *User) {
 			continue
 		}
 		switch key {
-		case "nickname":
-			out.Username = string(in.String())
-		case "fullname":
-			out.FullName = string(in.String())
-		case "about":
-			out.About = string(in.String())
-		case "email":
-			out.Email = string(in.String())
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "Query":
+			out.Query = string(in.String())
+		case "Match":
+			out.Match = string(in.String())
+		case "ActiveSince":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ActiveSince).UnmarshalJSON(data))
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -128,57 +135,67 @@ func easyjsonD2b7633eDecodeDbProjectAppModels1(in *jlexer.Lexer, out *User) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels1(out *jwriter.Writer, in User) {
+func easyjsonD2b7633eEncodeDbProjectAppModels1(out *jwriter.Writer, in UserListQueryParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"nickname\":"
+		const prefix string = ",\"Limit\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Username))
+		out.Int(int(in.Limit))
 	}
 	{
-		const prefix string = ",\"fullname\":"
+		const prefix string = ",\"Since\":"
 		out.RawString(prefix)
-		out.String(string(in.FullName))
+		out.String(string(in.Since))
 	}
 	{
-		const prefix string = ",\"about\":"
+		const prefix string = ",\"Desc\":"
 		out.RawString(prefix)
-		out.String(string(in.About))
+		out.Bool(bool(in.Desc))
 	}
 	{
-		const prefix string = ",\"email\":"
+		const prefix string = ",\"Query\":"
 		out.RawString(prefix)
-		out.String(string(in.Email))
+		out.String(string(in.Query))
+	}
+	{
+		const prefix string = ",\"Match\":"
+		out.RawString(prefix)
+		out.String(string(in.Match))
+	}
+	{
+		const prefix string = ",\"ActiveSince\":"
+		out.RawString(prefix)
+		out.Raw((in.ActiveSince).MarshalJSON())
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v User) MarshalJSON() ([]byte, error) {
+func (v UserListQueryParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeDbProjectAppModels1(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v User) MarshalEasyJSON(w *jwriter.Writer) {
+func (v UserListQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeDbProjectAppModels1(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *User) UnmarshalJSON(data []byte) error {
+func (v *UserListQueryParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeDbProjectAppModels1(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *UserListQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels1(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels2(in *jlexer.Lexer, out *Thread) {
+func easyjsonD2b7633eDecodeDbProjectAppModels2(in *jlexer.Lexer, out *User) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -197,24 +214,18 @@ func easyjsonD2b7633eDecodeDbProjectAppModels2(in *jlexer.Lexer, out *Thread) {
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
-		case "slug":
-			out.Slug = string(in.String())
-		case "author":
-			out.Author = string(in.String())
-		case "forum":
-			out.Forum = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "message":
-			out.Msg = string(in.String())
-		case "created":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Created).UnmarshalJSON(data))
-			}
-		case "votes":
-			out.Votes = int(in.Int())
+		case "nickname":
+			out.Username = string(in.String())
+		case "fullname":
+			out.FullName = string(in.String())
+		case "about":
+			out.About = string(in.String())
+		case "email":
+			out.Email = string(in.String())
+		case "password":
+			out.Password = string(in.String())
+		case "current_password":
+			out.CurrentPassword = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -225,77 +236,67 @@ func easyjsonD2b7633eDecodeDbProjectAppModels2(in *jlexer.Lexer, out *Thread) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels2(out *jwriter.Writer, in Thread) {
+func easyjsonD2b7633eEncodeDbProjectAppModels2(out *jwriter.Writer, in User) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
-	}
-	{
-		const prefix string = ",\"slug\":"
-		out.RawString(prefix)
-		out.String(string(in.Slug))
-	}
-	{
-		const prefix string = ",\"author\":"
-		out.RawString(prefix)
-		out.String(string(in.Author))
+		out.String(string(in.Username))
 	}
 	{
-		const prefix string = ",\"forum\":"
+		const prefix string = ",\"fullname\":"
 		out.RawString(prefix)
-		out.String(string(in.Forum))
+		out.String(string(in.FullName))
 	}
 	{
-		const prefix string = ",\"title\":"
+		const prefix string = ",\"about\":"
 		out.RawString(prefix)
-		out.String(string(in.Title))
+		out.String(string(in.About))
 	}
-	{
-		const prefix string = ",\"message\":"
+	if in.Email != "" {
+		const prefix string = ",\"email\":"
 		out.RawString(prefix)
-		out.String(string(in.Msg))
+		out.String(string(in.Email))
 	}
-	{
-		const prefix string = ",\"created\":"
+	if in.Password != "" {
+		const prefix string = ",\"password\":"
 		out.RawString(prefix)
-		out.Raw((in.Created).MarshalJSON())
+		out.String(string(in.Password))
 	}
-	{
-		const prefix string = ",\"votes\":"
+	if in.CurrentPassword != "" {
+		const prefix string = ",\"current_password\":"
 		out.RawString(prefix)
-		out.Int(int(in.Votes))
+		out.String(string(in.CurrentPassword))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Thread) MarshalJSON() ([]byte, error) {
+func (v User) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeDbProjectAppModels2(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
+func (v User) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeDbProjectAppModels2(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Thread) UnmarshalJSON(data []byte) error {
+func (v *User) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeDbProjectAppModels2(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *User) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels2(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels3(in *jlexer.Lexer, out *PostsQueryParams) {
+func easyjsonD2b7633eDecodeDbProjectAppModels3(in *jlexer.Lexer, out *TriggerHealth) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -314,14 +315,10 @@ func easyjsonD2b7633eDecodeDbProjectAppModels3(in *jlexer.Lexer, out *PostsQuery
 			continue
 		}
 		switch key {
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = int(in.Int())
-		case "SortType":
-			out.SortType = constants.SortType(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "name":
+			out.Name = string(in.String())
+		case "present":
+			out.Present = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -332,135 +329,134 @@ func easyjsonD2b7633eDecodeDbProjectAppModels3(in *jlexer.Lexer, out *PostsQuery
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels3(out *jwriter.Writer, in PostsQueryParams) {
+func easyjsonD2b7633eEncodeDbProjectAppModels3(out *jwriter.Writer, in TriggerHealth) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Limit\":"
+		const prefix string = ",\"name\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Limit))
-	}
-	{
-		const prefix string = ",\"Since\":"
-		out.RawString(prefix)
-		out.Int(int(in.Since))
-	}
-	{
-		const prefix string = ",\"SortType\":"
-		out.RawString(prefix)
-		out.String(string(in.SortType))
+		out.String(string(in.Name))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"present\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.Bool(bool(in.Present))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v PostsQueryParams) MarshalJSON() ([]byte, error) {
+func (v TriggerHealth) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeDbProjectAppModels3(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v PostsQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TriggerHealth) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeDbProjectAppModels3(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *PostsQueryParams) UnmarshalJSON(data []byte) error {
+func (v *TriggerHealth) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeDbProjectAppModels3(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *PostsQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TriggerHealth) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels3(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels4(in *jlexer.Lexer, out *Posts) {
+func easyjsonD2b7633eDecodeDbProjectAppModels4(in *jlexer.Lexer, out *TokenPair) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
-		in.Skip()
-		*out = nil
-	} else {
-		in.Delim('[')
-		if *out == nil {
-			if !in.IsDelim(']') {
-				*out = make(Posts, 0, 8)
-			} else {
-				*out = Posts{}
-			}
-		} else {
-			*out = (*out)[:0]
+		if isTopLevel {
+			in.Consumed()
 		}
-		for !in.IsDelim(']') {
-			var v1 *Post
-			if in.IsNull() {
-				in.Skip()
-				v1 = nil
-			} else {
-				if v1 == nil {
-					v1 = new(Post)
-				}
-				(*v1).UnmarshalEasyJSON(in)
-			}
-			*out = append(*out, v1)
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
 			in.WantComma()
+			continue
 		}
-		in.Delim(']')
+		switch key {
+		case "access_token":
+			out.AccessToken = string(in.String())
+		case "token_type":
+			out.TokenType = string(in.String())
+		case "expires_in":
+			out.ExpiresIn = int(in.Int())
+		case "refresh_token":
+			out.RefreshToken = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
 	}
+	in.Delim('}')
 	if isTopLevel {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels4(out *jwriter.Writer, in Posts) {
-	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
-		out.RawString("null")
-	} else {
-		out.RawByte('[')
-		for v2, v3 := range in {
-			if v2 > 0 {
-				out.RawByte(',')
-			}
-			if v3 == nil {
-				out.RawString("null")
-			} else {
-				(*v3).MarshalEasyJSON(out)
-			}
-		}
-		out.RawByte(']')
+func easyjsonD2b7633eEncodeDbProjectAppModels4(out *jwriter.Writer, in TokenPair) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"access_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.AccessToken))
+	}
+	{
+		const prefix string = ",\"token_type\":"
+		out.RawString(prefix)
+		out.String(string(in.TokenType))
+	}
+	{
+		const prefix string = ",\"expires_in\":"
+		out.RawString(prefix)
+		out.Int(int(in.ExpiresIn))
 	}
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix)
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Posts) MarshalJSON() ([]byte, error) {
+func (v TokenPair) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeDbProjectAppModels4(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Posts) MarshalEasyJSON(w *jwriter.Writer) {
+func (v TokenPair) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeDbProjectAppModels4(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Posts) UnmarshalJSON(data []byte) error {
+func (v *TokenPair) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeDbProjectAppModels4(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Posts) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *TokenPair) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels4(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels5(in *jlexer.Lexer, out *Post) {
+func easyjsonD2b7633eDecodeDbProjectAppModels5(in *jlexer.Lexer, out *Thread) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -481,22 +477,24 @@ func easyjsonD2b7633eDecodeDbProjectAppModels5(in *jlexer.Lexer, out *Post) {
 		switch key {
 		case "id":
 			out.ID = int(in.Int())
-		case "parent":
-			out.Parent = int(in.Int())
+		case "slug":
+			out.Slug = string(in.String())
 		case "author":
 			out.Author = string(in.String())
 		case "forum":
 			out.Forum = string(in.String())
-		case "thread":
-			out.Thread = int(in.Int())
+		case "title":
+			out.Title = string(in.String())
+		case "message":
+			out.Msg = string(in.String())
 		case "created":
 			if data := in.Raw(); in.Ok() {
 				in.AddError((out.Created).UnmarshalJSON(data))
 			}
-		case "isEdited":
-			out.IsEdited = bool(in.Bool())
-		case "message":
-			out.Message = string(in.String())
+		case "votes":
+			out.Votes = int(in.Int())
+		case "isArchived":
+			out.IsArchived = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -507,7 +505,7 @@ func easyjsonD2b7633eDecodeDbProjectAppModels5(in *jlexer.Lexer, out *Post) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels5(out *jwriter.Writer, in Post) {
+func easyjsonD2b7633eEncodeDbProjectAppModels5(out *jwriter.Writer, in Thread) {
 	out.RawByte('{')
 	first := true
 	_ = first
@@ -517,9 +515,9 @@ func easyjsonD2b7633eEncodeDbProjectAppModels5(out *jwriter.Writer, in Post) {
 		out.Int(int(in.ID))
 	}
 	{
-		const prefix string = ",\"parent\":"
+		const prefix string = ",\"slug\":"
 		out.RawString(prefix)
-		out.Int(int(in.Parent))
+		out.String(string(in.Slug))
 	}
 	{
 		const prefix string = ",\"author\":"
@@ -532,52 +530,57 @@ func easyjsonD2b7633eEncodeDbProjectAppModels5(out *jwriter.Writer, in Post) {
 		out.String(string(in.Forum))
 	}
 	{
-		const prefix string = ",\"thread\":"
+		const prefix string = ",\"title\":"
 		out.RawString(prefix)
-		out.Int(int(in.Thread))
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Msg))
 	}
 	{
 		const prefix string = ",\"created\":"
 		out.RawString(prefix)
 		out.Raw((in.Created).MarshalJSON())
 	}
-	if in.IsEdited {
-		const prefix string = ",\"isEdited\":"
+	{
+		const prefix string = ",\"votes\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.IsEdited))
+		out.Int(int(in.Votes))
 	}
-	{
-		const prefix string = ",\"message\":"
+	if in.IsArchived {
+		const prefix string = ",\"isArchived\":"
 		out.RawString(prefix)
-		out.String(string(in.Message))
+		out.Bool(bool(in.IsArchived))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Post) MarshalJSON() ([]byte, error) {
+func (v Thread) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
 	easyjsonD2b7633eEncodeDbProjectAppModels5(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
+func (v Thread) MarshalEasyJSON(w *jwriter.Writer) {
 	easyjsonD2b7633eEncodeDbProjectAppModels5(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Post) UnmarshalJSON(data []byte) error {
+func (v *Thread) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
 	easyjsonD2b7633eDecodeDbProjectAppModels5(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
+func (v *Thread) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonD2b7633eDecodeDbProjectAppModels5(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels6(in *jlexer.Lexer, out *ParamsPost) {
+func easyjsonD2b7633eDecodeDbProjectAppModels6(in *jlexer.Lexer, out *StatusQueryParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -596,45 +599,1876 @@ func easyjsonD2b7633eDecodeDbProjectAppModels6(in *jlexer.Lexer, out *ParamsPost
 			continue
 		}
 		switch key {
-		case "post":
-			if in.IsNull() {
-				in.Skip()
-				out.Post = nil
-			} else {
-				if out.Post == nil {
-					out.Post = new(Post)
-				}
-				(*out.Post).UnmarshalEasyJSON(in)
-			}
+		case "Exact":
+			out.Exact = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels6(out *jwriter.Writer, in StatusQueryParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Exact\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Exact))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v StatusQueryParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels6(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v StatusQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels6(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *StatusQueryParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels6(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *StatusQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels6(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels7(in *jlexer.Lexer, out *RefreshRequest) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "refresh_token":
+			out.RefreshToken = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels7(out *jwriter.Writer, in RefreshRequest) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"refresh_token\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.RefreshToken))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v RefreshRequest) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels7(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v RefreshRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels7(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *RefreshRequest) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels7(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *RefreshRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels7(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels8(in *jlexer.Lexer, out *Problem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "status":
+			out.Status = int(in.Int())
+		case "detail":
+			out.Detail = string(in.String())
+		case "instance":
+			out.Instance = string(in.String())
+		case "code":
+			out.Code = string(in.String())
+		case "errors":
+			if in.IsNull() {
+				in.Skip()
+				out.Errors = nil
+			} else {
+				in.Delim('[')
+				if out.Errors == nil {
+					if !in.IsDelim(']') {
+						out.Errors = make([]FieldError, 0, 1)
+					} else {
+						out.Errors = []FieldError{}
+					}
+				} else {
+					out.Errors = (out.Errors)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 FieldError
+					(v1).UnmarshalEasyJSON(in)
+					out.Errors = append(out.Errors, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels8(out *jwriter.Writer, in Problem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.Int(int(in.Status))
+	}
+	if in.Detail != "" {
+		const prefix string = ",\"detail\":"
+		out.RawString(prefix)
+		out.String(string(in.Detail))
+	}
+	if in.Instance != "" {
+		const prefix string = ",\"instance\":"
+		out.RawString(prefix)
+		out.String(string(in.Instance))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
+		out.String(string(in.Code))
+	}
+	if len(in.Errors) != 0 {
+		const prefix string = ",\"errors\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v2, v3 := range in.Errors {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				(v3).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Problem) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels8(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Problem) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels8(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Problem) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels8(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Problem) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels8(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels9(in *jlexer.Lexer, out *PostsQueryParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = int(in.Int())
+		case "SortType":
+			out.SortType = constants.SortType(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels9(out *jwriter.Writer, in PostsQueryParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.Int(int(in.Since))
+	}
+	{
+		const prefix string = ",\"SortType\":"
+		out.RawString(prefix)
+		out.String(string(in.SortType))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PostsQueryParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels9(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PostsQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels9(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PostsQueryParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels9(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PostsQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels9(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels10(in *jlexer.Lexer, out *Posts) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		in.Skip()
+		*out = nil
+	} else {
+		in.Delim('[')
+		if *out == nil {
+			if !in.IsDelim(']') {
+				*out = make(Posts, 0, 8)
+			} else {
+				*out = Posts{}
+			}
+		} else {
+			*out = (*out)[:0]
+		}
+		for !in.IsDelim(']') {
+			var v4 *Post
+			if in.IsNull() {
+				in.Skip()
+				v4 = nil
+			} else {
+				if v4 == nil {
+					v4 = new(Post)
+				}
+				(*v4).UnmarshalEasyJSON(in)
+			}
+			*out = append(*out, v4)
+			in.WantComma()
+		}
+		in.Delim(']')
+	}
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels10(out *jwriter.Writer, in Posts) {
+	if in == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.RawString("null")
+	} else {
+		out.RawByte('[')
+		for v5, v6 := range in {
+			if v5 > 0 {
+				out.RawByte(',')
+			}
+			if v6 == nil {
+				out.RawString("null")
+			} else {
+				(*v6).MarshalEasyJSON(out)
+			}
+		}
+		out.RawByte(']')
+	}
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Posts) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels10(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Posts) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels10(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Posts) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels10(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Posts) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels10(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels11(in *jlexer.Lexer, out *Post) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "parent":
+			out.Parent = int(in.Int())
+		case "author":
+			out.Author = string(in.String())
+		case "forum":
+			out.Forum = string(in.String())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "isEdited":
+			out.IsEdited = bool(in.Bool())
+		case "message":
+			out.Message = string(in.String())
+		case "isDeleted":
+			out.IsDeleted = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels11(out *jwriter.Writer, in Post) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ID))
+	}
+	{
+		const prefix string = ",\"parent\":"
+		out.RawString(prefix)
+		out.Int(int(in.Parent))
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		out.String(string(in.Author))
+	}
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if in.IsEdited {
+		const prefix string = ",\"isEdited\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsEdited))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	if in.IsDeleted {
+		const prefix string = ",\"isDeleted\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsDeleted))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Post) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels11(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Post) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels11(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Post) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels11(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Post) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels11(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels12(in *jlexer.Lexer, out *PoolHealth) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "acquired":
+			out.Acquired = int32(in.Int32())
+		case "idle":
+			out.Idle = int32(in.Int32())
+		case "total":
+			out.Total = int32(in.Int32())
+		case "max":
+			out.Max = int32(in.Int32())
+		case "saturation":
+			out.Saturation = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels12(out *jwriter.Writer, in PoolHealth) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"acquired\":"
+		out.RawString(prefix[1:])
+		out.Int32(int32(in.Acquired))
+	}
+	{
+		const prefix string = ",\"idle\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Idle))
+	}
+	{
+		const prefix string = ",\"total\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Total))
+	}
+	{
+		const prefix string = ",\"max\":"
+		out.RawString(prefix)
+		out.Int32(int32(in.Max))
+	}
+	{
+		const prefix string = ",\"saturation\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Saturation))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v PoolHealth) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels12(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v PoolHealth) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels12(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *PoolHealth) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels12(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *PoolHealth) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels12(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels13(in *jlexer.Lexer, out *ParamsPost) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "post":
+			if in.IsNull() {
+				in.Skip()
+				out.Post = nil
+			} else {
+				if out.Post == nil {
+					out.Post = new(Post)
+				}
+				(*out.Post).UnmarshalEasyJSON(in)
+			}
 		case "author":
 			if in.IsNull() {
 				in.Skip()
-				out.Author = nil
+				out.Author = nil
+			} else {
+				if out.Author == nil {
+					out.Author = new(User)
+				}
+				(*out.Author).UnmarshalEasyJSON(in)
+			}
+		case "thread":
+			if in.IsNull() {
+				in.Skip()
+				out.Thread = nil
+			} else {
+				if out.Thread == nil {
+					out.Thread = new(Thread)
+				}
+				(*out.Thread).UnmarshalEasyJSON(in)
+			}
+		case "forum":
+			if in.IsNull() {
+				in.Skip()
+				out.Forum = nil
+			} else {
+				if out.Forum == nil {
+					out.Forum = new(Forum)
+				}
+				(*out.Forum).UnmarshalEasyJSON(in)
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels13(out *jwriter.Writer, in ParamsPost) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	if in.Post != nil {
+		const prefix string = ",\"post\":"
+		first = false
+		out.RawString(prefix[1:])
+		(*in.Post).MarshalEasyJSON(out)
+	}
+	if in.Author != nil {
+		const prefix string = ",\"author\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Author).MarshalEasyJSON(out)
+	}
+	if in.Thread != nil {
+		const prefix string = ",\"thread\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Thread).MarshalEasyJSON(out)
+	}
+	if in.Forum != nil {
+		const prefix string = ",\"forum\":"
+		if first {
+			first = false
+			out.RawString(prefix[1:])
+		} else {
+			out.RawString(prefix)
+		}
+		(*in.Forum).MarshalEasyJSON(out)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ParamsPost) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels13(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ParamsPost) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels13(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ParamsPost) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels13(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ParamsPost) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels13(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels14(in *jlexer.Lexer, out *Moderator) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forum":
+			out.Forum = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "granted_by":
+			out.GrantedBy = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels14(out *jwriter.Writer, in Moderator) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	if in.GrantedBy != "" {
+		const prefix string = ",\"granted_by\":"
+		out.RawString(prefix)
+		out.String(string(in.GrantedBy))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Moderator) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels14(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Moderator) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels14(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Moderator) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels14(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Moderator) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels14(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels15(in *jlexer.Lexer, out *MigrationsHealth) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "current":
+			out.Current = bool(in.Bool())
+		case "error":
+			out.Error = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels15(out *jwriter.Writer, in MigrationsHealth) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"current\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Current))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v MigrationsHealth) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels15(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v MigrationsHealth) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels15(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *MigrationsHealth) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels15(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *MigrationsHealth) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels15(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels16(in *jlexer.Lexer, out *Message) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "code":
+			out.Code = string(in.String())
+		case "message":
+			out.Msg = string(in.String())
+		case "message_details":
+			out.TextDetails = string(in.String())
+		case "fields":
+			if in.IsNull() {
+				in.Skip()
+				out.Fields = nil
+			} else {
+				in.Delim('[')
+				if out.Fields == nil {
+					if !in.IsDelim(']') {
+						out.Fields = make([]FieldError, 0, 1)
+					} else {
+						out.Fields = []FieldError{}
+					}
+				} else {
+					out.Fields = (out.Fields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 FieldError
+					(v7).UnmarshalEasyJSON(in)
+					out.Fields = append(out.Fields, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels16(out *jwriter.Writer, in Message) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Code))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Msg))
+	}
+	if in.TextDetails != "" {
+		const prefix string = ",\"message_details\":"
+		out.RawString(prefix)
+		out.String(string(in.TextDetails))
+	}
+	if len(in.Fields) != 0 {
+		const prefix string = ",\"fields\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v8, v9 := range in.Fields {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				(v9).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Message) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels16(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels16(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Message) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels16(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels16(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels17(in *jlexer.Lexer, out *HealthStatus) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "reason":
+			out.Reason = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels17(out *jwriter.Writer, in HealthStatus) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	if in.Reason != "" {
+		const prefix string = ",\"reason\":"
+		out.RawString(prefix)
+		out.String(string(in.Reason))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HealthStatus) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels17(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HealthStatus) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels17(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HealthStatus) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels17(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HealthStatus) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels17(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels18(in *jlexer.Lexer, out *HealthReport) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "status":
+			out.Status = string(in.String())
+		case "database":
+			(out.Database).UnmarshalEasyJSON(in)
+		case "pool":
+			(out.Pool).UnmarshalEasyJSON(in)
+		case "migrations":
+			(out.Migrations).UnmarshalEasyJSON(in)
+		case "triggers":
+			if in.IsNull() {
+				in.Skip()
+				out.Triggers = nil
+			} else {
+				in.Delim('[')
+				if out.Triggers == nil {
+					if !in.IsDelim(']') {
+						out.Triggers = make([]TriggerHealth, 0, 2)
+					} else {
+						out.Triggers = []TriggerHealth{}
+					}
+				} else {
+					out.Triggers = (out.Triggers)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 TriggerHealth
+					(v10).UnmarshalEasyJSON(in)
+					out.Triggers = append(out.Triggers, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels18(out *jwriter.Writer, in HealthReport) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"database\":"
+		out.RawString(prefix)
+		(in.Database).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"pool\":"
+		out.RawString(prefix)
+		(in.Pool).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"migrations\":"
+		out.RawString(prefix)
+		(in.Migrations).MarshalEasyJSON(out)
+	}
+	{
+		const prefix string = ",\"triggers\":"
+		out.RawString(prefix)
+		if in.Triggers == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Triggers {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				(v12).MarshalEasyJSON(out)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v HealthReport) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels18(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v HealthReport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels18(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *HealthReport) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels18(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *HealthReport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels18(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels19(in *jlexer.Lexer, out *ForumUserQueryParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			out.Since = string(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels19(out *jwriter.Writer, in ForumUserQueryParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.String(string(in.Since))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumUserQueryParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels19(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumUserQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels19(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumUserQueryParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels19(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumUserQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels19(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels20(in *jlexer.Lexer, out *ForumUpdate) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "slug":
+			out.Slug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "description":
+			if in.IsNull() {
+				in.Skip()
+				out.Description = nil
+			} else {
+				if out.Description == nil {
+					out.Description = new(string)
+				}
+				*out.Description = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels20(out *jwriter.Writer, in ForumUpdate) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		if in.Description == nil {
+			out.RawString("null")
+		} else {
+			out.String(string(*in.Description))
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumUpdate) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels20(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumUpdate) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels20(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumUpdate) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels20(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumUpdate) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels20(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels21(in *jlexer.Lexer, out *ForumStatus) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forum":
+			out.Forum = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "thread":
+			out.Thread = int(in.Int())
+		case "user":
+			out.User = int(in.Int())
+		case "vote":
+			out.Vote = int(in.Int())
+		case "forum_user":
+			out.ForumUser = int(in.Int())
+		case "durability":
+			out.Durability = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels21(out *jwriter.Writer, in ForumStatus) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Forum))
+	}
+	{
+		const prefix string = ",\"post\":"
+		out.RawString(prefix)
+		out.Int(int(in.Post))
+	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.Int(int(in.User))
+	}
+	{
+		const prefix string = ",\"vote\":"
+		out.RawString(prefix)
+		out.Int(int(in.Vote))
+	}
+	{
+		const prefix string = ",\"forum_user\":"
+		out.RawString(prefix)
+		out.Int(int(in.ForumUser))
+	}
+	if in.Durability != "" {
+		const prefix string = ",\"durability\":"
+		out.RawString(prefix)
+		out.String(string(in.Durability))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumStatus) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels21(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumStatus) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels21(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumStatus) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels21(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumStatus) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels21(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels22(in *jlexer.Lexer, out *ForumQueryParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Since":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Since).UnmarshalJSON(data))
+			}
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "Archived":
+			out.Archived = bool(in.Bool())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels22(out *jwriter.Writer, in ForumQueryParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Since\":"
+		out.RawString(prefix)
+		out.Raw((in.Since).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	{
+		const prefix string = ",\"Archived\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Archived))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumQueryParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels22(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels22(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumQueryParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels22(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels22(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels23(in *jlexer.Lexer, out *ForumListQueryParams) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "Limit":
+			out.Limit = int(in.Int())
+		case "Sort":
+			out.Sort = constants.SortType(in.String())
+		case "Desc":
+			out.Desc = bool(in.Bool())
+		case "Query":
+			out.Query = string(in.String())
+		case "Cursor":
+			out.Cursor = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels23(out *jwriter.Writer, in ForumListQueryParams) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Limit\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Limit))
+	}
+	{
+		const prefix string = ",\"Sort\":"
+		out.RawString(prefix)
+		out.String(string(in.Sort))
+	}
+	{
+		const prefix string = ",\"Desc\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Desc))
+	}
+	{
+		const prefix string = ",\"Query\":"
+		out.RawString(prefix)
+		out.String(string(in.Query))
+	}
+	{
+		const prefix string = ",\"Cursor\":"
+		out.RawString(prefix)
+		out.String(string(in.Cursor))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumListQueryParams) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels23(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumListQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels23(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumListQueryParams) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels23(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumListQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels23(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels24(in *jlexer.Lexer, out *ForumList) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "forums":
+			if in.IsNull() {
+				in.Skip()
+				out.Forums = nil
 			} else {
-				if out.Author == nil {
-					out.Author = new(User)
+				in.Delim('[')
+				if out.Forums == nil {
+					if !in.IsDelim(']') {
+						out.Forums = make([]*Forum, 0, 8)
+					} else {
+						out.Forums = []*Forum{}
+					}
+				} else {
+					out.Forums = (out.Forums)[:0]
 				}
-				(*out.Author).UnmarshalEasyJSON(in)
-			}
-		case "thread":
-			if in.IsNull() {
-				in.Skip()
-				out.Thread = nil
-			} else {
-				if out.Thread == nil {
-					out.Thread = new(Thread)
+				for !in.IsDelim(']') {
+					var v13 *Forum
+					if in.IsNull() {
+						in.Skip()
+						v13 = nil
+					} else {
+						if v13 == nil {
+							v13 = new(Forum)
+						}
+						(*v13).UnmarshalEasyJSON(in)
+					}
+					out.Forums = append(out.Forums, v13)
+					in.WantComma()
 				}
-				(*out.Thread).UnmarshalEasyJSON(in)
+				in.Delim(']')
 			}
-		case "forum":
-			if in.IsNull() {
-				in.Skip()
-				out.Forum = nil
-			} else {
-				if out.Forum == nil {
-					out.Forum = new(Forum)
+		case "next":
+			out.Next = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels24(out *jwriter.Writer, in ForumList) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"forums\":"
+		out.RawString(prefix[1:])
+		if in.Forums == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Forums {
+				if v14 > 0 {
+					out.RawByte(',')
 				}
-				(*out.Forum).UnmarshalEasyJSON(in)
+				if v15 == nil {
+					out.RawString("null")
+				} else {
+					(*v15).MarshalEasyJSON(out)
+				}
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Next != "" {
+		const prefix string = ",\"next\":"
+		out.RawString(prefix)
+		out.String(string(in.Next))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v ForumList) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels24(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v ForumList) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels24(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *ForumList) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels24(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *ForumList) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels24(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels25(in *jlexer.Lexer, out *Forum) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = int(in.Int())
+		case "slug":
+			out.Slug = string(in.String())
+		case "title":
+			out.Title = string(in.String())
+		case "user":
+			out.User = string(in.String())
+		case "posts":
+			out.Posts = int(in.Int())
+		case "threads":
+			out.Threads = int(in.Int())
+		case "description":
+			out.Description = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
 			}
 		default:
 			in.SkipRecursive()
@@ -646,73 +2480,223 @@ func easyjsonD2b7633eDecodeDbProjectAppModels6(in *jlexer.Lexer, out *ParamsPost
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels6(out *jwriter.Writer, in ParamsPost) {
+func easyjsonD2b7633eEncodeDbProjectAppModels25(out *jwriter.Writer, in Forum) {
 	out.RawByte('{')
 	first := true
 	_ = first
-	if in.Post != nil {
-		const prefix string = ",\"post\":"
-		first = false
+	{
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		(*in.Post).MarshalEasyJSON(out)
+		out.Int(int(in.ID))
 	}
-	if in.Author != nil {
-		const prefix string = ",\"author\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	{
+		const prefix string = ",\"slug\":"
+		out.RawString(prefix)
+		out.String(string(in.Slug))
+	}
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix)
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		out.String(string(in.User))
+	}
+	{
+		const prefix string = ",\"posts\":"
+		out.RawString(prefix)
+		out.Int(int(in.Posts))
+	}
+	{
+		const prefix string = ",\"threads\":"
+		out.RawString(prefix)
+		out.Int(int(in.Threads))
+	}
+	if in.Description != "" {
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v Forum) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels25(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels25(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *Forum) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels25(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels25(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels26(in *jlexer.Lexer, out *FieldError) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
 		}
-		(*in.Author).MarshalEasyJSON(out)
+		in.Skip()
+		return
 	}
-	if in.Thread != nil {
-		const prefix string = ",\"thread\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "field":
+			out.Field = string(in.String())
+		case "rule":
+			out.Rule = string(in.String())
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels26(out *jwriter.Writer, in FieldError) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"field\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Field))
+	}
+	{
+		const prefix string = ",\"rule\":"
+		out.RawString(prefix)
+		out.String(string(in.Rule))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v FieldError) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjsonD2b7633eEncodeDbProjectAppModels26(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v FieldError) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels26(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *FieldError) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjsonD2b7633eDecodeDbProjectAppModels26(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *FieldError) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels26(l, v)
+}
+func easyjsonD2b7633eDecodeDbProjectAppModels27(in *jlexer.Lexer, out *Error) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "message":
+			out.Message = string(in.String())
+		default:
+			in.SkipRecursive()
 		}
-		(*in.Thread).MarshalEasyJSON(out)
+		in.WantComma()
 	}
-	if in.Forum != nil {
-		const prefix string = ",\"forum\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		(*in.Forum).MarshalEasyJSON(out)
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonD2b7633eEncodeDbProjectAppModels27(out *jwriter.Writer, in Error) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Message))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ParamsPost) MarshalJSON() ([]byte, error) {
+func (v Error) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels6(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels27(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ParamsPost) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels6(w, v)
+func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels27(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ParamsPost) UnmarshalJSON(data []byte) error {
+func (v *Error) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels6(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels27(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ParamsPost) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels6(l, v)
+func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels27(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels7(in *jlexer.Lexer, out *Message) {
+func easyjsonD2b7633eDecodeDbProjectAppModels28(in *jlexer.Lexer, out *DatabaseHealth) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -731,10 +2715,12 @@ func easyjsonD2b7633eDecodeDbProjectAppModels7(in *jlexer.Lexer, out *Message) {
 			continue
 		}
 		switch key {
-		case "message":
-			out.Msg = string(in.String())
-		case "message_details":
-			out.TextDetails = string(in.String())
+		case "reachable":
+			out.Reachable = bool(in.Bool())
+		case "latency_ms":
+			out.LatencyMs = float64(in.Float64())
+		case "error":
+			out.Error = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -745,52 +2731,52 @@ func easyjsonD2b7633eDecodeDbProjectAppModels7(in *jlexer.Lexer, out *Message) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels7(out *jwriter.Writer, in Message) {
+func easyjsonD2b7633eEncodeDbProjectAppModels28(out *jwriter.Writer, in DatabaseHealth) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
-		if first {
-			first = false
-			out.RawString(prefix[1:])
-		} else {
-			out.RawString(prefix)
-		}
-		out.String(string(in.Msg))
+		const prefix string = ",\"reachable\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.Reachable))
 	}
-	if in.TextDetails != "" {
-		const prefix string = ",\"message_details\":"
+	{
+		const prefix string = ",\"latency_ms\":"
 		out.RawString(prefix)
-		out.String(string(in.TextDetails))
+		out.Float64(float64(in.LatencyMs))
+	}
+	if in.Error != "" {
+		const prefix string = ",\"error\":"
+		out.RawString(prefix)
+		out.String(string(in.Error))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Message) MarshalJSON() ([]byte, error) {
+func (v DatabaseHealth) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels7(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels28(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Message) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels7(w, v)
+func (v DatabaseHealth) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels28(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Message) UnmarshalJSON(data []byte) error {
+func (v *DatabaseHealth) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels7(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels28(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Message) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels7(l, v)
+func (v *DatabaseHealth) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels28(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels8(in *jlexer.Lexer, out *ForumUserQueryParams) {
+func easyjsonD2b7633eDecodeDbProjectAppModels29(in *jlexer.Lexer, out *Credentials) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -809,12 +2795,10 @@ func easyjsonD2b7633eDecodeDbProjectAppModels8(in *jlexer.Lexer, out *ForumUserQ
 			continue
 		}
 		switch key {
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			out.Since = string(in.String())
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "nickname":
+			out.Username = string(in.String())
+		case "password":
+			out.Password = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -825,52 +2809,47 @@ func easyjsonD2b7633eDecodeDbProjectAppModels8(in *jlexer.Lexer, out *ForumUserQ
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels8(out *jwriter.Writer, in ForumUserQueryParams) {
+func easyjsonD2b7633eEncodeDbProjectAppModels29(out *jwriter.Writer, in Credentials) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Limit\":"
+		const prefix string = ",\"nickname\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Limit))
-	}
-	{
-		const prefix string = ",\"Since\":"
-		out.RawString(prefix)
-		out.String(string(in.Since))
+		out.String(string(in.Username))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"password\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.String(string(in.Password))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumUserQueryParams) MarshalJSON() ([]byte, error) {
+func (v Credentials) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels8(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels29(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumUserQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels8(w, v)
+func (v Credentials) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels29(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumUserQueryParams) UnmarshalJSON(data []byte) error {
+func (v *Credentials) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels8(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels29(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumUserQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels8(l, v)
+func (v *Credentials) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels29(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels9(in *jlexer.Lexer, out *ForumStatus) {
+func easyjsonD2b7633eDecodeDbProjectAppModels30(in *jlexer.Lexer, out *ClearReport) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -889,12 +2868,18 @@ func easyjsonD2b7633eDecodeDbProjectAppModels9(in *jlexer.Lexer, out *ForumStatu
 			continue
 		}
 		switch key {
+		case "dry_run":
+			out.DryRun = bool(in.Bool())
 		case "forum":
 			out.Forum = int(in.Int())
-		case "post":
-			out.Post = int(in.Int())
 		case "thread":
 			out.Thread = int(in.Int())
+		case "post":
+			out.Post = int(in.Int())
+		case "vote":
+			out.Vote = int(in.Int())
+		case "forum_user":
+			out.ForumUser = int(in.Int())
 		case "user":
 			out.User = int(in.Int())
 		default:
@@ -907,24 +2892,39 @@ func easyjsonD2b7633eDecodeDbProjectAppModels9(in *jlexer.Lexer, out *ForumStatu
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels9(out *jwriter.Writer, in ForumStatus) {
+func easyjsonD2b7633eEncodeDbProjectAppModels30(out *jwriter.Writer, in ClearReport) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"forum\":"
+		const prefix string = ",\"dry_run\":"
 		out.RawString(prefix[1:])
+		out.Bool(bool(in.DryRun))
+	}
+	{
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
 		out.Int(int(in.Forum))
 	}
+	{
+		const prefix string = ",\"thread\":"
+		out.RawString(prefix)
+		out.Int(int(in.Thread))
+	}
 	{
 		const prefix string = ",\"post\":"
 		out.RawString(prefix)
 		out.Int(int(in.Post))
 	}
 	{
-		const prefix string = ",\"thread\":"
+		const prefix string = ",\"vote\":"
 		out.RawString(prefix)
-		out.Int(int(in.Thread))
+		out.Int(int(in.Vote))
+	}
+	{
+		const prefix string = ",\"forum_user\":"
+		out.RawString(prefix)
+		out.Int(int(in.ForumUser))
 	}
 	{
 		const prefix string = ",\"user\":"
@@ -935,29 +2935,29 @@ func easyjsonD2b7633eEncodeDbProjectAppModels9(out *jwriter.Writer, in ForumStat
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumStatus) MarshalJSON() ([]byte, error) {
+func (v ClearReport) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels9(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels30(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumStatus) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels9(w, v)
+func (v ClearReport) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels30(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumStatus) UnmarshalJSON(data []byte) error {
+func (v *ClearReport) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels9(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels30(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumStatus) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels9(l, v)
+func (v *ClearReport) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels30(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels10(in *jlexer.Lexer, out *ForumQueryParams) {
+func easyjsonD2b7633eDecodeDbProjectAppModels31(in *jlexer.Lexer, out *ClearQueryParams) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -976,14 +2976,12 @@ func easyjsonD2b7633eDecodeDbProjectAppModels10(in *jlexer.Lexer, out *ForumQuer
 			continue
 		}
 		switch key {
-		case "Limit":
-			out.Limit = int(in.Int())
-		case "Since":
-			if data := in.Raw(); in.Ok() {
-				in.AddError((out.Since).UnmarshalJSON(data))
-			}
-		case "Desc":
-			out.Desc = bool(in.Bool())
+		case "Forum":
+			out.Forum = string(in.String())
+		case "Thread":
+			out.Thread = string(in.String())
+		case "DryRun":
+			out.DryRun = bool(in.Bool())
 		default:
 			in.SkipRecursive()
 		}
@@ -994,52 +2992,52 @@ func easyjsonD2b7633eDecodeDbProjectAppModels10(in *jlexer.Lexer, out *ForumQuer
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels10(out *jwriter.Writer, in ForumQueryParams) {
+func easyjsonD2b7633eEncodeDbProjectAppModels31(out *jwriter.Writer, in ClearQueryParams) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"Limit\":"
+		const prefix string = ",\"Forum\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.Limit))
+		out.String(string(in.Forum))
 	}
 	{
-		const prefix string = ",\"Since\":"
+		const prefix string = ",\"Thread\":"
 		out.RawString(prefix)
-		out.Raw((in.Since).MarshalJSON())
+		out.String(string(in.Thread))
 	}
 	{
-		const prefix string = ",\"Desc\":"
+		const prefix string = ",\"DryRun\":"
 		out.RawString(prefix)
-		out.Bool(bool(in.Desc))
+		out.Bool(bool(in.DryRun))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v ForumQueryParams) MarshalJSON() ([]byte, error) {
+func (v ClearQueryParams) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels10(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels31(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v ForumQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels10(w, v)
+func (v ClearQueryParams) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels31(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *ForumQueryParams) UnmarshalJSON(data []byte) error {
+func (v *ClearQueryParams) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels10(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels31(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *ForumQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels10(l, v)
+func (v *ClearQueryParams) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels31(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels11(in *jlexer.Lexer, out *Forum) {
+func easyjsonD2b7633eDecodeDbProjectAppModels32(in *jlexer.Lexer, out *APIKeyRequest) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1058,18 +3056,33 @@ func easyjsonD2b7633eDecodeDbProjectAppModels11(in *jlexer.Lexer, out *Forum) {
 			continue
 		}
 		switch key {
-		case "id":
-			out.ID = int(in.Int())
-		case "slug":
-			out.Slug = string(in.String())
-		case "title":
-			out.Title = string(in.String())
-		case "user":
-			out.User = string(in.String())
-		case "posts":
-			out.Posts = int(in.Int())
-		case "threads":
-			out.Threads = int(in.Int())
+		case "name":
+			out.Name = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v16 string
+					v16 = string(in.String())
+					out.Scopes = append(out.Scopes, v16)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "forum":
+			out.Forum = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1080,67 +3093,63 @@ func easyjsonD2b7633eDecodeDbProjectAppModels11(in *jlexer.Lexer, out *Forum) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels11(out *jwriter.Writer, in Forum) {
+func easyjsonD2b7633eEncodeDbProjectAppModels32(out *jwriter.Writer, in APIKeyRequest) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"id\":"
+		const prefix string = ",\"name\":"
 		out.RawString(prefix[1:])
-		out.Int(int(in.ID))
-	}
-	{
-		const prefix string = ",\"slug\":"
-		out.RawString(prefix)
-		out.String(string(in.Slug))
-	}
-	{
-		const prefix string = ",\"title\":"
-		out.RawString(prefix)
-		out.String(string(in.Title))
-	}
-	{
-		const prefix string = ",\"user\":"
-		out.RawString(prefix)
-		out.String(string(in.User))
+		out.String(string(in.Name))
 	}
 	{
-		const prefix string = ",\"posts\":"
+		const prefix string = ",\"scopes\":"
 		out.RawString(prefix)
-		out.Int(int(in.Posts))
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v17, v18 := range in.Scopes {
+				if v17 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v18))
+			}
+			out.RawByte(']')
+		}
 	}
 	{
-		const prefix string = ",\"threads\":"
+		const prefix string = ",\"forum\":"
 		out.RawString(prefix)
-		out.Int(int(in.Threads))
+		out.String(string(in.Forum))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Forum) MarshalJSON() ([]byte, error) {
+func (v APIKeyRequest) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels11(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels32(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Forum) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels11(w, v)
+func (v APIKeyRequest) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels32(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Forum) UnmarshalJSON(data []byte) error {
+func (v *APIKeyRequest) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels11(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels32(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Forum) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels11(l, v)
+func (v *APIKeyRequest) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels32(l, v)
 }
-func easyjsonD2b7633eDecodeDbProjectAppModels12(in *jlexer.Lexer, out *Error) {
+func easyjsonD2b7633eDecodeDbProjectAppModels33(in *jlexer.Lexer, out *APIKey) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
 		if isTopLevel {
@@ -1159,8 +3168,55 @@ func easyjsonD2b7633eDecodeDbProjectAppModels12(in *jlexer.Lexer, out *Error) {
 			continue
 		}
 		switch key {
-		case "message":
-			out.Message = string(in.String())
+		case "id":
+			out.ID = int64(in.Int64())
+		case "name":
+			out.Name = string(in.String())
+		case "prefix":
+			out.Prefix = string(in.String())
+		case "scopes":
+			if in.IsNull() {
+				in.Skip()
+				out.Scopes = nil
+			} else {
+				in.Delim('[')
+				if out.Scopes == nil {
+					if !in.IsDelim(']') {
+						out.Scopes = make([]string, 0, 4)
+					} else {
+						out.Scopes = []string{}
+					}
+				} else {
+					out.Scopes = (out.Scopes)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v19 string
+					v19 = string(in.String())
+					out.Scopes = append(out.Scopes, v19)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "forum":
+			out.Forum = string(in.String())
+		case "created":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Created).UnmarshalJSON(data))
+			}
+		case "last_used":
+			if in.IsNull() {
+				in.Skip()
+				out.LastUsed = nil
+			} else {
+				if out.LastUsed == nil {
+					out.LastUsed = new(time.Time)
+				}
+				if data := in.Raw(); in.Ok() {
+					in.AddError((*out.LastUsed).UnmarshalJSON(data))
+				}
+			}
+		case "key":
+			out.Key = string(in.String())
 		default:
 			in.SkipRecursive()
 		}
@@ -1171,38 +3227,84 @@ func easyjsonD2b7633eDecodeDbProjectAppModels12(in *jlexer.Lexer, out *Error) {
 		in.Consumed()
 	}
 }
-func easyjsonD2b7633eEncodeDbProjectAppModels12(out *jwriter.Writer, in Error) {
+func easyjsonD2b7633eEncodeDbProjectAppModels33(out *jwriter.Writer, in APIKey) {
 	out.RawByte('{')
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"message\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
-		out.String(string(in.Message))
+		out.Int64(int64(in.ID))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"prefix\":"
+		out.RawString(prefix)
+		out.String(string(in.Prefix))
+	}
+	{
+		const prefix string = ",\"scopes\":"
+		out.RawString(prefix)
+		if in.Scopes == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v20, v21 := range in.Scopes {
+				if v20 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v21))
+			}
+			out.RawByte(']')
+		}
+	}
+	if in.Forum != "" {
+		const prefix string = ",\"forum\":"
+		out.RawString(prefix)
+		out.String(string(in.Forum))
+	}
+	{
+		const prefix string = ",\"created\":"
+		out.RawString(prefix)
+		out.Raw((in.Created).MarshalJSON())
+	}
+	if in.LastUsed != nil {
+		const prefix string = ",\"last_used\":"
+		out.RawString(prefix)
+		out.Raw((*in.LastUsed).MarshalJSON())
+	}
+	if in.Key != "" {
+		const prefix string = ",\"key\":"
+		out.RawString(prefix)
+		out.String(string(in.Key))
 	}
 	out.RawByte('}')
 }
 
 // MarshalJSON supports json.Marshaler interface
-func (v Error) MarshalJSON() ([]byte, error) {
+func (v APIKey) MarshalJSON() ([]byte, error) {
 	w := jwriter.Writer{}
-	easyjsonD2b7633eEncodeDbProjectAppModels12(&w, v)
+	easyjsonD2b7633eEncodeDbProjectAppModels33(&w, v)
 	return w.Buffer.BuildBytes(), w.Error
 }
 
 // MarshalEasyJSON supports easyjson.Marshaler interface
-func (v Error) MarshalEasyJSON(w *jwriter.Writer) {
-	easyjsonD2b7633eEncodeDbProjectAppModels12(w, v)
+func (v APIKey) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjsonD2b7633eEncodeDbProjectAppModels33(w, v)
 }
 
 // UnmarshalJSON supports json.Unmarshaler interface
-func (v *Error) UnmarshalJSON(data []byte) error {
+func (v *APIKey) UnmarshalJSON(data []byte) error {
 	r := jlexer.Lexer{Data: data}
-	easyjsonD2b7633eDecodeDbProjectAppModels12(&r, v)
+	easyjsonD2b7633eDecodeDbProjectAppModels33(&r, v)
 	return r.Error()
 }
 
 // UnmarshalEasyJSON supports easyjson.Unmarshaler interface
-func (v *Error) UnmarshalEasyJSON(l *jlexer.Lexer) {
-	easyjsonD2b7633eDecodeDbProjectAppModels12(l, v)
+func (v *APIKey) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjsonD2b7633eDecodeDbProjectAppModels33(l, v)
 }
@@ -7,22 +7,24 @@ import (
 
 type Post struct {
 	ID       int       `json:"id"`
-	Parent   int       `json:"parent"`
-	Author   string    `json:"author"`
+	Parent   int       `json:"parent" validate:"min=0"`
+	Author   string    `json:"author" validate:"required,nickname,max=64"`
 	Forum    string    `json:"forum"`
 	Thread   int       `json:"thread"`
 	Created  time.Time `json:"created"`
 	IsEdited bool      `json:"isEdited,omitempty"`
-	Message  string    `json:"message"`
+	Message  string    `json:"message" validate:"required,max=65536"`
+	// IsDeleted marks a placeholder left by a deleted post, its message is empty.
+	IsDeleted bool `json:"isDeleted,omitempty"`
 }
 
 //easyjson:json
 type Posts []*Post
 
 type PostsQueryParams struct {
-	Limit    int                `form:"limit"`
-	Since    int                `form:"since"`
-	SortType constants.SortType `form:"sort"`
+	Limit    int                `form:"limit,default=100" validate:"min=1,max=10000"`
+	Since    int                `form:"since" validate:"min=0"`
+	SortType constants.SortType `form:"sort,default=flat" validate:"oneof=flat tree parent_tree"`
 	Desc     bool               `form:"desc"`
 }
 
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+type Moderator struct {
+	Forum     string    `json:"forum"`
+	Nickname  string    `json:"nickname"`
+	GrantedBy string    `json:"granted_by,omitempty"`
+	Created   time.Time `json:"created"`
+}
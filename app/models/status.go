@@ -1,8 +1,36 @@
 package models
 
 type ForumStatus struct {
-	Forum  int `json:"forum"`
-	Post   int `json:"post"`
-	Thread int `json:"thread"`
-	User   int `json:"user"`
+	Forum     int `json:"forum"`
+	Post      int `json:"post"`
+	Thread    int `json:"thread"`
+	User      int `json:"user"`
+	Vote      int `json:"vote"`
+	ForumUser int `json:"forum_user"`
+	// Durability is "logged", "unlogged" or "mixed" depending on how the tables are stored.
+	Durability string `json:"durability,omitempty"`
+}
+
+type StatusQueryParams struct {
+	// Exact counts the rows instead of reading the maintained counters.
+	Exact bool `form:"exact"`
+}
+
+type ClearQueryParams struct {
+	// Forum and Thread limit the clear to one forum or one thread.
+	Forum  string `form:"forum" validate:"omitempty,slug,max=128"`
+	Thread string `form:"thread" validate:"max=128"`
+	// DryRun reports what would be deleted without deleting it.
+	DryRun bool `form:"dry_run"`
+}
+
+// ClearReport counts the rows deleted, or to be deleted in a dry run.
+type ClearReport struct {
+	DryRun    bool `json:"dry_run"`
+	Forum     int  `json:"forum"`
+	Thread    int  `json:"thread"`
+	Post      int  `json:"post"`
+	Vote      int  `json:"vote"`
+	ForumUser int  `json:"forum_user"`
+	User      int  `json:"user"`
 }
@@ -4,11 +4,13 @@ import "time"
 
 type Thread struct {
 	ID      int       `json:"id"`
-	Slug    string    `json:"slug"`
-	Author  string    `json:"author"`
-	Forum   string    `json:"forum"`
-	Title   string    `json:"title"`
-	Msg     string    `json:"message"`
+	Slug    string    `json:"slug" validate:"omitempty,slug,max=128"`
+	Author  string    `json:"author" validate:"required,nickname,max=64"`
+	Forum   string    `json:"forum" validate:"required,slug,max=128"`
+	Title   string    `json:"title" validate:"required,max=256"`
+	Msg     string    `json:"message" validate:"required,max=65536"`
 	Created time.Time `json:"created"`
 	Votes   int       `json:"votes"`
+	// IsArchived marks a read-only thread.
+	IsArchived bool `json:"isArchived,omitempty"`
 }
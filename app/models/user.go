@@ -1,8 +1,29 @@
 package models
 
+import "time"
+
 type User struct {
-	Username string `json:"nickname"`
-	FullName string `json:"fullname"`
-	About    string `json:"about"`
-	Email    string `json:"email"`
+	Username string `json:"nickname" validate:"required,nickname,max=64"`
+	FullName string `json:"fullname" validate:"required,max=256"`
+	About    string `json:"about" validate:"max=4096"`
+	// Email is left out of the user directory.
+	Email string `json:"email,omitempty" validate:"required,email,max=254"`
+	// Password is only read from requests; bcrypt ignores bytes past 72.
+	Password string `json:"password,omitempty" validate:"omitempty,min=8,max=72"`
+	// CurrentPassword must come with Password when users change their own.
+	CurrentPassword string `json:"current_password,omitempty" validate:"max=72"`
+}
+
+type UserListQueryParams struct {
+	Limit int `form:"limit,default=100" validate:"min=1,max=1000"`
+	// Since and Desc page through the users by nickname, a fuzzy search
+	// returns only the closest matches.
+	Since string `form:"since" validate:"omitempty,nickname,max=64,excluded_if=Match fuzzy"`
+	Desc  bool   `form:"desc" validate:"excluded_if=Match fuzzy"`
+	// Query keeps the users whose nickname or a word of whose fullname starts
+	// with it or, when Match is fuzzy, resembles it.
+	Query string `form:"q" validate:"max=64"`
+	Match string `form:"match,default=prefix" validate:"oneof=prefix fuzzy"`
+	// ActiveSince keeps the users who posted or started a thread since then.
+	ActiveSince time.Time `form:"active_since"`
 }
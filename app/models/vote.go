@@ -1,6 +1,6 @@
 package models
 
 type Vote struct {
-	Username string `json:"nickname"`
-	Voice    int    `json:"voice"`
+	Username string `json:"nickname" validate:"required,nickname,max=64"`
+	Voice    int    `json:"voice" validate:"oneof=-1 1"`
 }
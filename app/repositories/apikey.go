@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/utils/constants"
+	"log/slog"
+	"time"
+)
+
+type IAPIKeyRepository interface {
+	Create(ctx context.Context, nickname string, request *models.APIKeyRequest, prefix string, hash string) (key *models.APIKey, err error)
+	List(ctx context.Context, nickname string) (keys []*models.APIKey, err error)
+	Revoke(ctx context.Context, nickname string, id int64) (revoked bool, err error)
+	GetByHash(ctx context.Context, hash string) (key *StoredAPIKey, err error)
+	Touch(ctx context.Context, id int64) (err error)
+}
+
+// StoredAPIKey is what authenticating with a key needs to know about it.
+type StoredAPIKey struct {
+	ID       int64
+	Nickname string
+	Scopes   []string
+	Forum    string
+	LastUsed *time.Time
+}
+
+type APIKeyRepository struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+func CreateAPIKeyRepository(db *DB, logger *slog.Logger) IAPIKeyRepository {
+	return &APIKeyRepository{db: db, logger: logger}
+}
+
+// Create stores a key of nickname. An unknown user violates the not-null
+// constraint, an unknown forum the foreign key.
+func (repo *APIKeyRepository) Create(ctx context.Context, nickname string, request *models.APIKeyRequest, prefix string, hash string) (key *models.APIKey, err error) {
+	row := repo.db.QueryRow(ctx, "APIKeyQuery.Create", constants.APIKeyQuery["Create"],
+		nickname, request.Name, prefix, hash, request.Scopes, request.Forum)
+
+	key = &models.APIKey{}
+	err = row.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.Forum, &key.Created, &key.LastUsed)
+	return
+}
+
+func (repo *APIKeyRepository) List(ctx context.Context, nickname string) (keys []*models.APIKey, err error) {
+	rows, err := repo.db.Query(ctx, "APIKeyQuery.List", constants.APIKeyQuery["List"], nickname)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	keys = make([]*models.APIKey, 0)
+	for rows.Next() {
+		key := &models.APIKey{}
+		if err = rows.Scan(&key.ID, &key.Name, &key.Prefix, &key.Scopes, &key.Forum, &key.Created, &key.LastUsed); err != nil {
+			keys = nil
+			return
+		}
+		keys = append(keys, key)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *APIKeyRepository) Revoke(ctx context.Context, nickname string, id int64) (revoked bool, err error) {
+	tag, err := repo.db.Exec(ctx, "APIKeyQuery.Revoke", constants.APIKeyQuery["Revoke"], id, nickname)
+	revoked = err == nil && tag.RowsAffected() > 0
+	return
+}
+
+// GetByHash returns the key with hash unless it is revoked. Unknown keys give pgx.ErrNoRows.
+func (repo *APIKeyRepository) GetByHash(ctx context.Context, hash string) (key *StoredAPIKey, err error) {
+	row := repo.db.QueryRow(ctx, "APIKeyQuery.GetByHash", constants.APIKeyQuery["GetByHash"], hash)
+
+	key = &StoredAPIKey{}
+	err = row.Scan(&key.ID, &key.Nickname, &key.Scopes, &key.Forum, &key.LastUsed)
+	return
+}
+
+func (repo *APIKeyRepository) Touch(ctx context.Context, id int64) (err error) {
+	_, err = repo.db.Exec(ctx, "APIKeyQuery.Touch", constants.APIKeyQuery["Touch"], id)
+	return
+}
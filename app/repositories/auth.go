@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"db_project/utils/constants"
+	"log/slog"
+	"time"
+)
+
+type IAuthRepository interface {
+	PasswordHash(ctx context.Context, nickname string) (storedNickname string, hash string, err error)
+	SetPassword(ctx context.Context, nickname string, hash string) (storedNickname string, err error)
+	CreateRefreshToken(ctx context.Context, hash string, nickname string, expiresAt time.Time) (err error)
+	UseRefreshToken(ctx context.Context, hash string) (nickname string, err error)
+	RevokeRefreshToken(ctx context.Context, hash string) (err error)
+}
+
+type AuthRepository struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+func CreateAuthRepository(db *DB, logger *slog.Logger) IAuthRepository {
+	return &AuthRepository{db: db, logger: logger}
+}
+
+// PasswordHash returns the nickname as stored, which may differ in case from
+// the given one, and the password hash, empty for users without a password.
+func (repo *AuthRepository) PasswordHash(ctx context.Context, nickname string) (storedNickname string, hash string, err error) {
+	err = repo.db.QueryRow(ctx, "AuthQuery.PasswordHash", constants.AuthQuery["PasswordHash"], nickname).Scan(&storedNickname, &hash)
+	return
+}
+
+// SetPassword replaces the password hash of a user. Unknown users give pgx.ErrNoRows.
+func (repo *AuthRepository) SetPassword(ctx context.Context, nickname string, hash string) (storedNickname string, err error) {
+	err = repo.db.QueryRow(ctx, "AuthQuery.SetPassword", constants.AuthQuery["SetPassword"], nickname, hash).Scan(&storedNickname)
+	return
+}
+
+func (repo *AuthRepository) CreateRefreshToken(ctx context.Context, hash string, nickname string, expiresAt time.Time) (err error) {
+	_, err = repo.db.Exec(ctx, "AuthQuery.CreateRefresh", constants.AuthQuery["CreateRefresh"], hash, nickname, expiresAt)
+	return
+}
+
+// UseRefreshToken revokes a valid refresh token and returns its user. Unknown,
+// expired and already used tokens give pgx.ErrNoRows.
+func (repo *AuthRepository) UseRefreshToken(ctx context.Context, hash string) (nickname string, err error) {
+	err = repo.db.QueryRow(ctx, "AuthQuery.UseRefresh", constants.AuthQuery["UseRefresh"], hash).Scan(&nickname)
+	return
+}
+
+func (repo *AuthRepository) RevokeRefreshToken(ctx context.Context, hash string) (err error) {
+	_, err = repo.db.Exec(ctx, "AuthQuery.RevokeRefresh", constants.AuthQuery["RevokeRefresh"], hash)
+	return
+}
@@ -0,0 +1,172 @@
+package repositories
+
+import (
+	"context"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"sync"
+)
+
+// QueryHook observes the queries run by the repositories. QueryStart may
+// return a derived context, which is passed to the query and to QueryEnd.
+type QueryHook interface {
+	QueryStart(ctx context.Context, name string) context.Context
+	QueryEnd(ctx context.Context, name string, err error)
+}
+
+// DB is the connection pool shared by the repositories. Every query runs
+// under a stable name such as "ThreadQuery.VoteBySlug" so that hooks can
+// tell the queries apart.
+type DB struct {
+	pool  *pgxpool.Pool
+	hooks []QueryHook
+}
+
+func CreateDB(pool *pgxpool.Pool, hooks ...QueryHook) *DB {
+	return &DB{pool: pool, hooks: hooks}
+}
+
+func (db *DB) Pool() *pgxpool.Pool {
+	return db.pool
+}
+
+func (db *DB) QueryRow(ctx context.Context, name string, sql string, args ...interface{}) pgx.Row {
+	ctx, end := db.start(ctx, name)
+	return &row{row: db.pool.QueryRow(ctx, sql, args...), end: end}
+}
+
+func (db *DB) Query(ctx context.Context, name string, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, end := db.start(ctx, name)
+	result, err := db.pool.Query(ctx, sql, args...)
+	if err != nil {
+		end(err)
+	}
+	return &rows{Rows: result, end: end}, err
+}
+
+func (db *DB) Exec(ctx context.Context, name string, sql string, args ...interface{}) (tag pgconn.CommandTag, err error) {
+	ctx, end := db.start(ctx, name)
+	tag, err = db.pool.Exec(ctx, sql, args...)
+	end(err)
+	return
+}
+
+func (db *DB) Begin(ctx context.Context) (*Tx, error) {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Tx{tx: tx, db: db}, nil
+}
+
+// Tx is a transaction whose queries are named like the ones run through DB.
+type Tx struct {
+	tx pgx.Tx
+	db *DB
+}
+
+func (tx *Tx) QueryRow(ctx context.Context, name string, sql string, args ...interface{}) pgx.Row {
+	ctx, end := tx.db.start(ctx, name)
+	return &row{row: tx.tx.QueryRow(ctx, sql, args...), end: end}
+}
+
+func (tx *Tx) Query(ctx context.Context, name string, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, end := tx.db.start(ctx, name)
+	result, err := tx.tx.Query(ctx, sql, args...)
+	if err != nil {
+		end(err)
+	}
+	return &rows{Rows: result, end: end}, err
+}
+
+func (tx *Tx) Exec(ctx context.Context, name string, sql string, args ...interface{}) (tag pgconn.CommandTag, err error) {
+	ctx, end := tx.db.start(ctx, name)
+	tag, err = tx.tx.Exec(ctx, sql, args...)
+	end(err)
+	return
+}
+
+// SendBatch runs the queued queries under one name. The batch is observed
+// until its results are closed.
+func (tx *Tx) SendBatch(ctx context.Context, name string, batch *pgx.Batch) pgx.BatchResults {
+	ctx, end := tx.db.start(ctx, name)
+	return &batchResults{BatchResults: tx.tx.SendBatch(ctx, batch), end: end}
+}
+
+func (tx *Tx) Commit(ctx context.Context) error {
+	return tx.tx.Commit(ctx)
+}
+
+func (tx *Tx) Rollback(ctx context.Context) error {
+	return tx.tx.Rollback(ctx)
+}
+
+// start notifies the hooks that a query begins and returns the function that
+// reports its end. The returned function may be called more than once, only
+// the first call is reported.
+func (db *DB) start(ctx context.Context, name string) (context.Context, func(err error)) {
+	if len(db.hooks) == 0 {
+		return ctx, func(error) {}
+	}
+
+	for _, hook := range db.hooks {
+		ctx = hook.QueryStart(ctx, name)
+	}
+
+	var once sync.Once
+	return ctx, func(err error) {
+		once.Do(func() {
+			if err == pgx.ErrNoRows {
+				err = nil
+			}
+			for i := len(db.hooks) - 1; i >= 0; i-- {
+				db.hooks[i].QueryEnd(ctx, name, err)
+			}
+		})
+	}
+}
+
+type row struct {
+	row pgx.Row
+	end func(err error)
+}
+
+func (r *row) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	r.end(err)
+	return err
+}
+
+type rows struct {
+	pgx.Rows
+	end func(err error)
+}
+
+func (r *rows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.end(r.Rows.Err())
+	return false
+}
+
+func (r *rows) Close() {
+	r.Rows.Close()
+	r.end(r.Rows.Err())
+}
+
+type batchResults struct {
+	pgx.BatchResults
+	end func(err error)
+}
+
+func (b *batchResults) Close() error {
+	err := b.BatchResults.Close()
+	b.end(err)
+	return err
+}
+
+// likeEscaper makes a string match itself in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -4,29 +4,33 @@ import (
 	"context"
 	"db_project/app/models"
 	"db_project/utils/constants"
+	"fmt"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
 	"time"
 )
 
 type ForumRepository struct {
-	db *pgxpool.Pool
+	db     *DB
+	logger *slog.Logger
 }
 
-func CreateForumRepository(db *pgxpool.Pool) IForumRepository {
-	return &ForumRepository{db: db}
+func CreateForumRepository(db *DB, logger *slog.Logger) IForumRepository {
+	return &ForumRepository{db: db, logger: logger}
 }
 
 type IForumRepository interface {
-	CreateThread(thread *models.Thread) (createdThread *models.Thread, err error)
-	GetThreads(slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error)
-	GetUsers(slug string, params *models.ForumUserQueryParams) (users []*models.User, err error)
-	Create(forum *models.Forum) (createdForum *models.Forum, err error)
-	Get(slug string) (forum *models.Forum, err error)
+	CreateThread(ctx context.Context, thread *models.Thread) (createdThread *models.Thread, err error)
+	GetThreads(ctx context.Context, slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error)
+	GetUsers(ctx context.Context, slug string, params *models.ForumUserQueryParams) (users []*models.User, err error)
+	Create(ctx context.Context, forum *models.Forum) (createdForum *models.Forum, err error)
+	Get(ctx context.Context, slug string) (forum *models.Forum, err error)
+	Update(ctx context.Context, slug string, forum *models.ForumUpdate) (updatedForum *models.Forum, err error)
+	List(ctx context.Context, params *models.ForumListQueryParams, after string, afterSlug string) (forums []*models.Forum, err error)
 }
 
-func (repo *ForumRepository) Create(forum *models.Forum) (createdForum *models.Forum, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ForumQuery["Create"], forum.Slug, forum.Title, forum.User)
+func (repo *ForumRepository) Create(ctx context.Context, forum *models.Forum) (createdForum *models.Forum, err error) {
+	row := repo.db.QueryRow(ctx, "ForumQuery.Create", constants.ForumQuery["Create"], forum.Slug, forum.Title, forum.User, forum.Description)
 
 	createdForum = &models.Forum{}
 	err = row.Scan(
@@ -34,12 +38,14 @@ func (repo *ForumRepository) Create(forum *models.Forum) (createdForum *models.F
 		&createdForum.Title,
 		&createdForum.User,
 		&createdForum.Posts,
-		&createdForum.Threads)
+		&createdForum.Threads,
+		&createdForum.Description,
+		&createdForum.Created)
 	return
 }
 
-func (repo *ForumRepository) Get(slug string) (forum *models.Forum, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ForumQuery["Get"], slug)
+func (repo *ForumRepository) Get(ctx context.Context, slug string) (forum *models.Forum, err error) {
+	row := repo.db.QueryRow(ctx, "ForumQuery.Get", constants.ForumQuery["Get"], slug)
 
 	forum = &models.Forum{}
 	err = row.Scan(
@@ -48,13 +54,34 @@ func (repo *ForumRepository) Get(slug string) (forum *models.Forum, err error) {
 		&forum.Title,
 		&forum.User,
 		&forum.Posts,
-		&forum.Threads)
+		&forum.Threads,
+		&forum.Description,
+		&forum.Created)
 	return
 }
 
-func (repo *ForumRepository) CreateThread(thread *models.Thread) (createdThread *models.Thread, err error) {
+// Update changes the non-empty fields of forum and the description when it
+// is set, renaming the forum when forum.Slug differs from slug.
+func (repo *ForumRepository) Update(ctx context.Context, slug string, forum *models.ForumUpdate) (updatedForum *models.Forum, err error) {
+	row := repo.db.QueryRow(ctx, "ForumQuery.Update", constants.ForumQuery["Update"],
+		slug, forum.Slug, forum.Title, forum.Description, forum.User)
 
-	row := repo.db.QueryRow(context.Background(), constants.ForumQuery["CreateThread"], thread.Slug, thread.Author, thread.Forum, thread.Title, thread.Msg, thread.Created)
+	updatedForum = &models.Forum{}
+	err = row.Scan(
+		&updatedForum.ID,
+		&updatedForum.Slug,
+		&updatedForum.Title,
+		&updatedForum.User,
+		&updatedForum.Posts,
+		&updatedForum.Threads,
+		&updatedForum.Description,
+		&updatedForum.Created)
+	return
+}
+
+func (repo *ForumRepository) CreateThread(ctx context.Context, thread *models.Thread) (createdThread *models.Thread, err error) {
+
+	row := repo.db.QueryRow(ctx, "ForumQuery.CreateThread", constants.ForumQuery["CreateThread"], thread.Slug, thread.Author, thread.Forum, thread.Title, thread.Msg, thread.Created)
 
 	createdThread = &models.Thread{}
 	err = row.Scan(
@@ -69,23 +96,29 @@ func (repo *ForumRepository) CreateThread(thread *models.Thread) (createdThread
 	return
 }
 
-func (repo *ForumRepository) GetThreads(slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error) {
+func (repo *ForumRepository) GetThreads(ctx context.Context, slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error) {
 	query := constants.ForumQuery["GetThreads"]
+	if !params.Archived {
+		query += constants.ForumQuery["GetThreadsActive"]
+	}
 	var rows pgx.Rows
+	var variant string
 	if !params.Since.Equal(time.Time{}) {
 		if params.Desc {
-			query += constants.ForumQuery["GetThreadsDesc"]
+			variant = "GetThreadsDesc"
 		} else {
-			query += constants.ForumQuery["GetThreadsNoDesc"]
+			variant = "GetThreadsNoDesc"
 		}
-		rows, err = repo.db.Query(context.Background(), query, slug, params.Since, params.Limit)
+		query += constants.ForumQuery[constants.SortType(variant)]
+		rows, err = repo.db.Query(ctx, "ForumQuery."+variant, query, slug, params.Since, params.Limit)
 	} else {
 		if params.Desc {
-			query += constants.ForumQuery["GetThreadsSinceDesc"]
+			variant = "GetThreadsSinceDesc"
 		} else {
-			query += constants.ForumQuery["GetThreadsSinceNoDesc"]
+			variant = "GetThreadsSinceNoDesc"
 		}
-		rows, err = repo.db.Query(context.Background(), query, slug, params.Limit)
+		query += constants.ForumQuery[constants.SortType(variant)]
+		rows, err = repo.db.Query(ctx, "ForumQuery."+variant, query, slug, params.Limit)
 	}
 
 	defer rows.Close()
@@ -104,7 +137,8 @@ func (repo *ForumRepository) GetThreads(slug string, params *models.ForumQueryPa
 			&thread.Title,
 			&thread.Msg,
 			&thread.Created,
-			&thread.Votes)
+			&thread.Votes,
+			&thread.IsArchived)
 		if err != nil {
 			threads = nil
 			return
@@ -115,24 +149,27 @@ func (repo *ForumRepository) GetThreads(slug string, params *models.ForumQueryPa
 	return
 }
 
-func (repo *ForumRepository) GetUsers(slug string, params *models.ForumUserQueryParams) (users []*models.User, err error) {
+func (repo *ForumRepository) GetUsers(ctx context.Context, slug string, params *models.ForumUserQueryParams) (users []*models.User, err error) {
 	query := constants.ForumQuery["GetUsers"]
 
 	var rows pgx.Rows
+	var variant string
 	if params.Since != "" {
 		if params.Desc {
-			query += constants.ForumQuery["GetUsersSinceDesc"]
+			variant = "GetUsersSinceDesc"
 		} else {
-			query += constants.ForumQuery["GetUsersSinceNoDesc"]
+			variant = "GetUsersSinceNoDesc"
 		}
-		rows, err = repo.db.Query(context.Background(), query, slug, params.Since, params.Limit)
+		query += constants.ForumQuery[constants.SortType(variant)]
+		rows, err = repo.db.Query(ctx, "ForumQuery."+variant, query, slug, params.Since, params.Limit)
 	} else {
 		if params.Desc {
-			query += constants.ForumQuery["GetUsersDesc"]
+			variant = "GetUsersDesc"
 		} else {
-			query += constants.ForumQuery["GetUsersNoDesc"]
+			variant = "GetUsersNoDesc"
 		}
-		rows, err = repo.db.Query(context.Background(), query, slug, params.Limit)
+		query += constants.ForumQuery[constants.SortType(variant)]
+		rows, err = repo.db.Query(ctx, "ForumQuery."+variant, query, slug, params.Limit)
 	}
 
 	defer rows.Close()
@@ -157,3 +194,58 @@ func (repo *ForumRepository) GetUsers(slug string, params *models.ForumUserQuery
 
 	return
 }
+
+// List returns a page of forums ordered by params.Sort and the slug. The page
+// starts after the forum with sort value after and slug afterSlug, or at the
+// beginning when afterSlug is empty.
+func (repo *ForumRepository) List(ctx context.Context, params *models.ForumListQueryParams, after string, afterSlug string) (forums []*models.Forum, err error) {
+	column := constants.ForumListColumns[params.Sort]
+	pattern := ""
+	if params.Query != "" {
+		pattern = "%" + likeEscaper.Replace(params.Query) + "%"
+	}
+
+	query := constants.ForumListQuery["List"]
+	args := []interface{}{pattern}
+	if afterSlug != "" {
+		keyset := "After"
+		if params.Desc {
+			keyset = "Before"
+		}
+		query += fmt.Sprintf(constants.ForumListQuery[constants.SortType(keyset)], column[0], column[1])
+		args = append(args, after, afterSlug)
+	}
+	order := "Order"
+	if params.Desc {
+		order = "OrderDesc"
+	}
+	args = append(args, params.Limit)
+	query += fmt.Sprintf(constants.ForumListQuery[constants.SortType(order)], column[0], column[1], len(args))
+
+	rows, err := repo.db.Query(ctx, "ForumListQuery."+string(params.Sort), query, args...)
+	defer rows.Close()
+	if err != nil {
+		return
+	}
+
+	forums = make([]*models.Forum, 0)
+	for rows.Next() {
+		forum := &models.Forum{}
+		err = rows.Scan(
+			&forum.ID,
+			&forum.Slug,
+			&forum.Title,
+			&forum.User,
+			&forum.Posts,
+			&forum.Threads,
+			&forum.Description,
+			&forum.Created)
+		if err != nil {
+			forums = nil
+			return
+		}
+		forums = append(forums, forum)
+	}
+	err = rows.Err()
+	return
+}
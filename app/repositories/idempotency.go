@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"context"
+	"db_project/utils/constants"
+	"db_project/utils/idempotency"
+	"log/slog"
+	"time"
+)
+
+// IdempotencyRepository is the idempotency.Store of the service.
+type IdempotencyRepository struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+func CreateIdempotencyRepository(db *DB, logger *slog.Logger) idempotency.Store {
+	return &IdempotencyRepository{db: db, logger: logger}
+}
+
+func (repo *IdempotencyRepository) Claim(ctx context.Context, scope string, key string, fingerprint string, expired time.Time, lease time.Duration) (claimed bool, stored *idempotency.Response, err error) {
+	row := repo.db.QueryRow(ctx, "IdempotencyQuery.Claim", constants.IdempotencyQuery["Claim"],
+		scope, key, fingerprint, expired, lease.Seconds())
+
+	response := &idempotency.Response{}
+	if err = row.Scan(&claimed, &response.Fingerprint, &response.Status, &response.ContentType, &response.Body); err != nil {
+		return
+	}
+	if !claimed && response.Fingerprint != "" {
+		stored = response
+	}
+	return
+}
+
+func (repo *IdempotencyRepository) Complete(ctx context.Context, scope string, key string, response *idempotency.Response) (err error) {
+	_, err = repo.db.Exec(ctx, "IdempotencyQuery.Complete", constants.IdempotencyQuery["Complete"],
+		scope, key, response.Status, response.ContentType, response.Body)
+	return
+}
+
+func (repo *IdempotencyRepository) Release(ctx context.Context, scope string, key string) (err error) {
+	_, err = repo.db.Exec(ctx, "IdempotencyQuery.Release", constants.IdempotencyQuery["Release"], scope, key)
+	return
+}
+
+func (repo *IdempotencyRepository) Forget(ctx context.Context, before time.Time) (err error) {
+	_, err = repo.db.Exec(ctx, "IdempotencyQuery.Forget", constants.IdempotencyQuery["Forget"], before)
+	return
+}
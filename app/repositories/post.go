@@ -4,24 +4,38 @@ import (
 	"context"
 	"db_project/app/models"
 	"db_project/utils/constants"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
 )
 
 type IPostRepository interface {
-	Get(id int) (post *models.Post, err error)
-	Update(post *models.Post) (updatedPost *models.Post, err error)
+	Get(ctx context.Context, id int) (post *models.Post, err error)
+	GetVisible(ctx context.Context, id int) (post *models.Post, err error)
+	Update(ctx context.Context, post *models.Post) (updatedPost *models.Post, err error)
+	Delete(ctx context.Context, id int) (err error)
+	Restore(ctx context.Context, id int) (err error)
+	Purge(ctx context.Context, id int) (hasReplies bool, err error)
 }
 
 type PostRepository struct {
-	db *pgxpool.Pool
+	db     *DB
+	logger *slog.Logger
 }
 
-func CreatePostRepository(db *pgxpool.Pool) IPostRepository {
-	return &PostRepository{db: db}
+func CreatePostRepository(db *DB, logger *slog.Logger) IPostRepository {
+	return &PostRepository{db: db, logger: logger}
 }
 
-func (repo *PostRepository) Get(id int) (post *models.Post, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.PostQuery["Get"], id)
+func (repo *PostRepository) Get(ctx context.Context, id int) (post *models.Post, err error) {
+	return repo.get(ctx, "PostQuery.Get", constants.PostQuery["Get"], id)
+}
+
+// GetVisible is Get for readers: posts of deleted threads give pgx.ErrNoRows.
+func (repo *PostRepository) GetVisible(ctx context.Context, id int) (post *models.Post, err error) {
+	return repo.get(ctx, "PostQuery.GetVisible", constants.PostQuery["GetVisible"], id)
+}
+
+func (repo *PostRepository) get(ctx context.Context, name string, query string, id int) (post *models.Post, err error) {
+	row := repo.db.QueryRow(ctx, name, query, id)
 	post = &models.Post{}
 	err = row.Scan(
 		&post.ID,
@@ -31,12 +45,13 @@ func (repo *PostRepository) Get(id int) (post *models.Post, err error) {
 		&post.Thread,
 		&post.Created,
 		&post.IsEdited,
-		&post.Message)
+		&post.Message,
+		&post.IsDeleted)
 	return
 }
 
-func (repo *PostRepository) Update(post *models.Post) (updatedPost *models.Post, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.PostQuery["Update"], post.Message, post.ID)
+func (repo *PostRepository) Update(ctx context.Context, post *models.Post) (updatedPost *models.Post, err error) {
+	row := repo.db.QueryRow(ctx, "PostQuery.Update", constants.PostQuery["Update"], post.Message, post.ID)
 
 	updatedPost = &models.Post{}
 	err = row.Scan(
@@ -50,3 +65,53 @@ func (repo *PostRepository) Update(post *models.Post) (updatedPost *models.Post,
 		&updatedPost.Message)
 	return
 }
+
+// Delete marks a post deleted. Its row stays, so its replies keep their place.
+func (repo *PostRepository) Delete(ctx context.Context, id int) (err error) {
+	_, err = repo.db.Exec(ctx, "PostQuery.Delete", constants.PostQuery["Delete"], id)
+	return
+}
+
+func (repo *PostRepository) Restore(ctx context.Context, id int) (err error) {
+	_, err = repo.db.Exec(ctx, "PostQuery.Restore", constants.PostQuery["Restore"], id)
+	return
+}
+
+// Purge removes a post without replies for good and drops its author from
+// the forum users if it was their last post there. A post with replies is
+// left alone. It returns pgx.ErrNoRows when the post doesn't exist.
+func (repo *PostRepository) Purge(ctx context.Context, id int) (hasReplies bool, err error) {
+	tx, err := repo.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit(ctx)
+		} else if trErr := tx.Rollback(ctx); trErr != nil {
+			repo.logger.WarnContext(ctx, "rollback failed",
+				slog.String("error", trErr.Error()), slog.String("cause", err.Error()))
+		}
+	}()
+
+	var forum, author string
+	var counted bool
+	if err = tx.QueryRow(ctx, "PostQuery.PurgeLock", constants.PostQuery["PurgeLock"], id).Scan(&forum, &author, &counted); err != nil {
+		return
+	}
+	if err = tx.QueryRow(ctx, "PostQuery.PurgeReplies", constants.PostQuery["PurgeReplies"], id).Scan(&hasReplies); err != nil || hasReplies {
+		return
+	}
+	if _, err = tx.Exec(ctx, "PostQuery.Purge", constants.PostQuery["Purge"], id); err != nil {
+		return
+	}
+	if counted {
+		if _, err = tx.Exec(ctx, "PostQuery.PurgeCounter", constants.PostQuery["PurgeCounter"], forum); err != nil {
+			return
+		}
+	}
+	var stale int
+	err = tx.QueryRow(ctx, "ServiceQuery.ClearStaleForumUsers", constants.ServiceQuery["ClearStaleForumUsers"],
+		forum, []string{author}).Scan(&stale)
+	return
+}
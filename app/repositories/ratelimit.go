@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"db_project/utils/constants"
+	"db_project/utils/ratelimit"
+	"log/slog"
+	"time"
+)
+
+// RateLimitRepository is the ratelimit.Store shared by every instance using
+// the database. Each request costs one upsert.
+type RateLimitRepository struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+func CreateRateLimitRepository(db *DB, logger *slog.Logger) ratelimit.Store {
+	return &RateLimitRepository{db: db, logger: logger}
+}
+
+func (repo *RateLimitRepository) Take(ctx context.Context, key string, budget ratelimit.Budget, cost int) (tokens float64, allowed bool, err error) {
+	row := repo.db.QueryRow(ctx, "RateLimitQuery.Take", constants.RateLimitQuery["Take"], key, budget.Burst, cost, budget.Rate)
+	err = row.Scan(&tokens, &allowed)
+	return
+}
+
+func (repo *RateLimitRepository) Forget(ctx context.Context, before time.Time) (err error) {
+	_, err = repo.db.Exec(ctx, "RateLimitQuery.Forget", constants.RateLimitQuery["Forget"], before)
+	return
+}
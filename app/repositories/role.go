@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/utils/constants"
+	"log/slog"
+)
+
+type IRoleRepository interface {
+	Role(ctx context.Context, nickname string, forum string) (role string, err error)
+	Moderators(ctx context.Context, forum string) (moderators []*models.Moderator, err error)
+	GrantModerator(ctx context.Context, forum string, nickname string, grantedBy string) (moderator *models.Moderator, err error)
+	RevokeModerator(ctx context.Context, forum string, nickname string) (revoked bool, err error)
+	Admins(ctx context.Context) (nicknames []string, err error)
+	GrantAdmin(ctx context.Context, nickname string) (err error)
+	RevokeAdmin(ctx context.Context, nickname string) (revoked bool, err error)
+}
+
+type RoleRepository struct {
+	db     *DB
+	logger *slog.Logger
+}
+
+func CreateRoleRepository(db *DB, logger *slog.Logger) IRoleRepository {
+	return &RoleRepository{db: db, logger: logger}
+}
+
+// Role returns the highest role of nickname in forum: admin, owner, moderator or member.
+func (repo *RoleRepository) Role(ctx context.Context, nickname string, forum string) (role string, err error) {
+	err = repo.db.QueryRow(ctx, "RoleQuery.Role", constants.RoleQuery["Role"], nickname, forum).Scan(&role)
+	return
+}
+
+func (repo *RoleRepository) Moderators(ctx context.Context, forum string) (moderators []*models.Moderator, err error) {
+	rows, err := repo.db.Query(ctx, "RoleQuery.Moderators", constants.RoleQuery["Moderators"], forum)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	moderators = make([]*models.Moderator, 0)
+	for rows.Next() {
+		moderator := &models.Moderator{}
+		if err = rows.Scan(&moderator.Forum, &moderator.Nickname, &moderator.GrantedBy, &moderator.Created); err != nil {
+			moderators = nil
+			return
+		}
+		moderators = append(moderators, moderator)
+	}
+	err = rows.Err()
+	return
+}
+
+// GrantModerator makes nickname a moderator of forum. Granting it again keeps
+// the original grant. An unknown user violates the not-null constraint.
+func (repo *RoleRepository) GrantModerator(ctx context.Context, forum string, nickname string, grantedBy string) (moderator *models.Moderator, err error) {
+	row := repo.db.QueryRow(ctx, "RoleQuery.GrantModerator", constants.RoleQuery["GrantModerator"], forum, nickname, grantedBy)
+
+	moderator = &models.Moderator{}
+	err = row.Scan(&moderator.Forum, &moderator.Nickname, &moderator.GrantedBy, &moderator.Created)
+	return
+}
+
+func (repo *RoleRepository) RevokeModerator(ctx context.Context, forum string, nickname string) (revoked bool, err error) {
+	tag, err := repo.db.Exec(ctx, "RoleQuery.RevokeModerator", constants.RoleQuery["RevokeModerator"], forum, nickname)
+	revoked = err == nil && tag.RowsAffected() > 0
+	return
+}
+
+func (repo *RoleRepository) Admins(ctx context.Context) (nicknames []string, err error) {
+	rows, err := repo.db.Query(ctx, "RoleQuery.Admins", constants.RoleQuery["Admins"])
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	nicknames = make([]string, 0)
+	for rows.Next() {
+		var nickname string
+		if err = rows.Scan(&nickname); err != nil {
+			nicknames = nil
+			return
+		}
+		nicknames = append(nicknames, nickname)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *RoleRepository) GrantAdmin(ctx context.Context, nickname string) (err error) {
+	_, err = repo.db.Exec(ctx, "RoleQuery.GrantAdmin", constants.RoleQuery["GrantAdmin"], nickname)
+	return
+}
+
+func (repo *RoleRepository) RevokeAdmin(ctx context.Context, nickname string) (revoked bool, err error) {
+	tag, err := repo.db.Exec(ctx, "RoleQuery.RevokeAdmin", constants.RoleQuery["RevokeAdmin"], nickname)
+	revoked = err == nil && tag.RowsAffected() > 0
+	return
+}
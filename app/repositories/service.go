@@ -4,29 +4,233 @@ import (
 	"context"
 	"db_project/app/models"
 	"db_project/utils/constants"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"fmt"
+	"log/slog"
 )
 
 type IServiceRepository interface {
-	Clear() (err error)
-	Status() (status *models.ForumStatus, err error)
+	Clear(ctx context.Context) (err error)
+	KeptUsers(ctx context.Context) (count int, err error)
+	ClearForum(ctx context.Context, slug string, dryRun bool) (report *models.ClearReport, err error)
+	ClearThread(ctx context.Context, thread *models.Thread, dryRun bool) (report *models.ClearReport, err error)
+	Status(ctx context.Context) (status *models.ForumStatus, err error)
+	ExactStatus(ctx context.Context) (status *models.ForumStatus, err error)
+	CompactCounters(ctx context.Context) (err error)
+	Durability(ctx context.Context) (mode string, err error)
+	SetDurability(ctx context.Context, mode string) (err error)
+	Ping(ctx context.Context) (err error)
+	Triggers(ctx context.Context) (names []string, err error)
+	Pool() (pool *models.PoolHealth)
 }
 
 type ServiceRepository struct {
-	db *pgxpool.Pool
+	db     *DB
+	logger *slog.Logger
 }
 
-func CreateServiceRepository(db *pgxpool.Pool) IServiceRepository {
-	return &ServiceRepository{db: db}
+func CreateServiceRepository(db *DB, logger *slog.Logger) IServiceRepository {
+	return &ServiceRepository{db: db, logger: logger}
 }
 
-func (repo *ServiceRepository) Clear() (err error) {
-	_, err = repo.db.Exec(context.Background(), constants.ServiceQuery["Clear"])
+// Clear deletes everything but the users with a role, their roles and their
+// refresh tokens, so that the admin clearing the database isn't locked out.
+func (repo *ServiceRepository) Clear(ctx context.Context) (err error) {
+	tx, err := repo.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() { err = repo.finish(ctx, tx, err) }()
+
+	if _, err = tx.Exec(ctx, "ServiceQuery.Clear", constants.ServiceQuery["Clear"]); err != nil {
+		return
+	}
+	if _, err = tx.Exec(ctx, "ServiceQuery.ClearRefreshTokens", constants.ServiceQuery["ClearRefreshTokens"]); err != nil {
+		return
+	}
+	_, err = tx.Exec(ctx, "ServiceQuery.ClearUsers", constants.ServiceQuery["ClearUsers"])
+	return
+}
+
+// KeptUsers counts the users Clear keeps.
+func (repo *ServiceRepository) KeptUsers(ctx context.Context) (count int, err error) {
+	err = repo.db.QueryRow(ctx, "ServiceQuery.KeptUsers", constants.ServiceQuery["KeptUsers"]).Scan(&count)
+	return
+}
+
+// ClearForum deletes a forum with its threads, posts, votes, forum users and
+// moderators, and revokes the API keys restricted to it.
+// A dry run only counts them, without locking anything.
+func (repo *ServiceRepository) ClearForum(ctx context.Context, slug string, dryRun bool) (report *models.ClearReport, err error) {
+	if dryRun {
+		report = &models.ClearReport{DryRun: true, Forum: 1}
+		err = repo.db.QueryRow(ctx, "ServiceQuery.CountForum", constants.ServiceQuery["CountForum"], slug).
+			Scan(&report.Vote, &report.Post, &report.Thread, &report.ForumUser)
+		if err != nil {
+			report = nil
+		}
+		return
+	}
+
+	tx, err := repo.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() { err = repo.finish(ctx, tx, err) }()
+
+	if err = tx.QueryRow(ctx, "ServiceQuery.LockForum", constants.ServiceQuery["LockForum"], slug).Scan(&slug); err != nil {
+		return
+	}
+
+	report = &models.ClearReport{Forum: 1}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearForumVotes", constants.ServiceQuery["ClearForumVotes"], slug).Scan(&report.Vote); err != nil {
+		report = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearForumPosts", constants.ServiceQuery["ClearForumPosts"], slug).Scan(&report.Post); err != nil {
+		report = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearForumThreads", constants.ServiceQuery["ClearForumThreads"], slug).Scan(&report.Thread); err != nil {
+		report = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearForumUsers", constants.ServiceQuery["ClearForumUsers"], slug).Scan(&report.ForumUser); err != nil {
+		report = nil
+		return
+	}
+	if _, err = tx.Exec(ctx, "ServiceQuery.ClearForumModerators", constants.ServiceQuery["ClearForumModerators"], slug); err != nil {
+		report = nil
+		return
+	}
+	if _, err = tx.Exec(ctx, "ServiceQuery.ClearForumAPIKeys", constants.ServiceQuery["ClearForumAPIKeys"], slug); err != nil {
+		report = nil
+		return
+	}
+	if _, err = tx.Exec(ctx, "ServiceQuery.ClearForum", constants.ServiceQuery["ClearForum"], slug); err != nil {
+		report = nil
+		return
+	}
 	return
 }
 
-func (repo *ServiceRepository) Status() (status *models.ForumStatus, err error) {
-	ctx := context.Background()
+// ClearThread deletes a thread with its posts and votes, updates the forum
+// counters and drops the forum users left without threads or posts there.
+// A dry run only counts them, without locking anything.
+func (repo *ServiceRepository) ClearThread(ctx context.Context, thread *models.Thread, dryRun bool) (report *models.ClearReport, err error) {
+	if dryRun {
+		report = &models.ClearReport{DryRun: true, Thread: 1}
+		err = repo.db.QueryRow(ctx, "ServiceQuery.CountThread", constants.ServiceQuery["CountThread"], thread.ID).
+			Scan(&report.Vote, &report.Post, &report.ForumUser)
+		if err != nil {
+			report = nil
+		}
+		return
+	}
+
+	tx, err := repo.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() { err = repo.finish(ctx, tx, err) }()
+
+	var forum string
+	var live bool
+	if err = tx.QueryRow(ctx, "ServiceQuery.LockThread", constants.ServiceQuery["LockThread"], thread.ID).Scan(&forum, &live); err != nil {
+		return
+	}
+
+	report = &models.ClearReport{Thread: 1}
+	var authors []string
+	var threadAuthor string
+	// Deleted posts, and deleted threads with all their posts, are already
+	// left out of the forum counters.
+	var counted int
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearThreadVotes", constants.ServiceQuery["ClearThreadVotes"], thread.ID).Scan(&report.Vote); err != nil {
+		report = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearThreadPosts", constants.ServiceQuery["ClearThreadPosts"], thread.ID).Scan(&report.Post, &counted, &authors); err != nil {
+		report = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearThread", constants.ServiceQuery["ClearThread"], thread.ID).Scan(&threadAuthor); err != nil {
+		report = nil
+		return
+	}
+	threads := 1
+	if !live {
+		threads, counted = 0, 0
+	}
+	if _, err = tx.Exec(ctx, "ServiceQuery.ClearThreadCounters", constants.ServiceQuery["ClearThreadCounters"], forum, threads, counted); err != nil {
+		report = nil
+		return
+	}
+	authors = append(authors, threadAuthor)
+	if err = tx.QueryRow(ctx, "ServiceQuery.ClearStaleForumUsers", constants.ServiceQuery["ClearStaleForumUsers"], forum, authors).Scan(&report.ForumUser); err != nil {
+		report = nil
+		return
+	}
+	return
+}
+
+// finish commits tx unless err is set.
+func (repo *ServiceRepository) finish(ctx context.Context, tx *Tx, err error) error {
+	if err == nil {
+		return tx.Commit(ctx)
+	}
+	if trErr := tx.Rollback(ctx); trErr != nil {
+		repo.logger.WarnContext(ctx, "rollback failed",
+			slog.String("error", trErr.Error()), slog.String("cause", err.Error()))
+	}
+	return err
+}
+
+// Status sums the row counts maintained by the counters triggers.
+func (repo *ServiceRepository) Status(ctx context.Context) (status *models.ForumStatus, err error) {
+	rows, err := repo.db.Query(ctx, "ServiceQuery.Counters", constants.ServiceQuery["Counters"])
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	status = &models.ForumStatus{}
+	for rows.Next() {
+		var name string
+		var value int
+		if err = rows.Scan(&name, &value); err != nil {
+			status = nil
+			return
+		}
+		switch name {
+		case "users":
+			status.User = value
+		case "forums":
+			status.Forum = value
+		case "forum_users":
+			status.ForumUser = value
+		case "threads":
+			status.Thread = value
+		case "votes":
+			status.Vote = value
+		case "posts":
+			status.Post = value
+		}
+	}
+	if err = rows.Err(); err != nil {
+		status = nil
+	}
+	return
+}
+
+// CompactCounters folds the changes appended by the counters triggers into
+// one row per table, so Status sums few rows.
+func (repo *ServiceRepository) CompactCounters(ctx context.Context) (err error) {
+	_, err = repo.db.Exec(ctx, "ServiceQuery.CompactCounters", constants.ServiceQuery["CompactCounters"])
+	return
+}
+
+// ExactStatus counts the rows of every table, which scans them.
+func (repo *ServiceRepository) ExactStatus(ctx context.Context) (status *models.ForumStatus, err error) {
 	tx, err := repo.db.Begin(ctx)
 	if err != nil {
 		return
@@ -40,28 +244,149 @@ func (repo *ServiceRepository) Status() (status *models.ForumStatus, err error)
 		} else {
 			trErr := tx.Rollback(ctx)
 			if trErr != nil {
-				err = trErr
+				repo.logger.WarnContext(ctx, "rollback failed",
+					slog.String("error", trErr.Error()), slog.String("cause", err.Error()))
 			}
 		}
 	}()
 
 	status = &models.ForumStatus{}
-	if err = tx.QueryRow(ctx, constants.ServiceQuery["queryUsers"]).Scan(&status.User); err != nil {
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryUsers", constants.ServiceQuery["queryUsers"]).Scan(&status.User); err != nil {
+		status = nil
+		return
+	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryForums", constants.ServiceQuery["queryForums"]).Scan(&status.Forum); err != nil {
 		status = nil
 		return
 	}
-	if err = tx.QueryRow(ctx, constants.ServiceQuery["queryForums"]).Scan(&status.Forum); err != nil {
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryThreads", constants.ServiceQuery["queryThreads"]).Scan(&status.Thread); err != nil {
 		status = nil
 		return
 	}
-	if err = tx.QueryRow(ctx, constants.ServiceQuery["queryThreads"]).Scan(&status.Thread); err != nil {
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryPosts", constants.ServiceQuery["queryPosts"]).Scan(&status.Post); err != nil {
 		status = nil
 		return
 	}
-	if err = tx.QueryRow(ctx, constants.ServiceQuery["queryPosts"]).Scan(&status.Post); err != nil {
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryVotes", constants.ServiceQuery["queryVotes"]).Scan(&status.Vote); err != nil {
 		status = nil
 		return
 	}
+	if err = tx.QueryRow(ctx, "ServiceQuery.queryForumUsers", constants.ServiceQuery["queryForumUsers"]).Scan(&status.ForumUser); err != nil {
+		status = nil
+		return
+	}
+
+	return
+}
+
+func (repo *ServiceRepository) Durability(ctx context.Context) (mode string, err error) {
+	rows, err := repo.db.Query(ctx, "ServiceQuery.Durability", constants.ServiceQuery["Durability"], constants.DataTables)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	logged, unlogged := 0, 0
+	for rows.Next() {
+		var table string
+		var isLogged bool
+		if err = rows.Scan(&table, &isLogged); err != nil {
+			return
+		}
+		if isLogged {
+			logged++
+		} else {
+			unlogged++
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return
+	}
 
+	switch {
+	case unlogged == 0:
+		mode = constants.DurabilityLogged
+	case logged == 0:
+		mode = constants.DurabilityUnlogged
+	default:
+		mode = constants.DurabilityMixed
+	}
+	return
+}
+
+// SetDurability rewrites every table as logged or unlogged. It takes an
+// exclusive lock on each table for the duration of the rewrite.
+func (repo *ServiceRepository) SetDurability(ctx context.Context, mode string) (err error) {
+	tx, err := repo.db.Begin(ctx)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit(ctx)
+		} else if trErr := tx.Rollback(ctx); trErr != nil {
+			repo.logger.WarnContext(ctx, "rollback failed",
+				slog.String("error", trErr.Error()), slog.String("cause", err.Error()))
+		}
+	}()
+
+	// A logged table can't reference an unlogged one, so parents are converted
+	// first when switching to logged and last when switching to unlogged.
+	switch mode {
+	case constants.DurabilityLogged:
+		for _, table := range constants.DataTables {
+			if _, err = tx.Exec(ctx, "ServiceQuery.SetLogged", fmt.Sprintf(constants.ServiceQuery["SetLogged"], table)); err != nil {
+				return
+			}
+		}
+	case constants.DurabilityUnlogged:
+		for i := len(constants.DataTables) - 1; i >= 0; i-- {
+			if _, err = tx.Exec(ctx, "ServiceQuery.SetUnlogged", fmt.Sprintf(constants.ServiceQuery["SetUnlogged"], constants.DataTables[i])); err != nil {
+				return
+			}
+		}
+	default:
+		err = fmt.Errorf("unknown durability mode %q", mode)
+	}
+	return
+}
+
+func (repo *ServiceRepository) Ping(ctx context.Context) (err error) {
+	_, err = repo.db.Exec(ctx, "ServiceQuery.Ping", constants.ServiceQuery["Ping"])
+	return
+}
+
+// Triggers returns the names of the user-defined triggers in the public schema.
+func (repo *ServiceRepository) Triggers(ctx context.Context) (names []string, err error) {
+	rows, err := repo.db.Query(ctx, "ServiceQuery.Triggers", constants.ServiceQuery["Triggers"])
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	names = make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			names = nil
+			return
+		}
+		names = append(names, name)
+	}
+	err = rows.Err()
+	return
+}
+
+func (repo *ServiceRepository) Pool() (pool *models.PoolHealth) {
+	stat := repo.db.Pool().Stat()
+	pool = &models.PoolHealth{
+		Acquired: stat.AcquiredConns(),
+		Idle:     stat.IdleConns(),
+		Total:    stat.TotalConns(),
+		Max:      stat.MaxConns(),
+	}
+	if pool.Max > 0 {
+		pool.Saturation = float64(pool.Acquired) / float64(pool.Max)
+	}
 	return
 }
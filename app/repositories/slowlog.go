@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type slowQueryStartKey struct{}
+
+// SlowQueryLog logs every query that takes longer than threshold together
+// with its name, e.g. ThreadQuery.VoteBySlug.
+type SlowQueryLog struct {
+	logger    *slog.Logger
+	threshold time.Duration
+}
+
+func CreateSlowQueryLog(logger *slog.Logger, threshold time.Duration) *SlowQueryLog {
+	return &SlowQueryLog{logger: logger, threshold: threshold}
+}
+
+func (l *SlowQueryLog) QueryStart(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, slowQueryStartKey{}, time.Now())
+}
+
+func (l *SlowQueryLog) QueryEnd(ctx context.Context, name string, err error) {
+	start, ok := ctx.Value(slowQueryStartKey{}).(time.Time)
+	if !ok {
+		return
+	}
+
+	duration := time.Since(start)
+	if duration < l.threshold {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", name),
+		slog.Duration("duration", duration),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	l.logger.LogAttrs(ctx, slog.LevelWarn, "slow query", attrs...)
+}
@@ -6,72 +6,76 @@ import (
 	"db_project/utils/constants"
 	"fmt"
 	"github.com/jackc/pgx/v4"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
 	"strings"
 	"time"
 )
 
 type IThreadRepository interface {
-	GetBySlug(slug string) (thread *models.Thread, err error)
-	UpdateByID(thread *models.Thread) (updatedThread *models.Thread, err error)
-	GetByID(id int) (thread *models.Thread, err error)
-	CreatePosts(threadId int, forumSlug string, post []*models.Post) (createdPosts []*models.Post, err error)
-	GetPosts(threadId int, params *models.PostsQueryParams) (posts []*models.Post, err error)
-	VoteBySlug(slug string, vote *models.Vote) (err error)
-	UpdateBySlug(thread *models.Thread) (updatedThread *models.Thread, err error)
-	VoteByID(threadId int, vote *models.Vote) (err error)
+	GetBySlug(ctx context.Context, slug string) (thread *models.Thread, err error)
+	UpdateByID(ctx context.Context, thread *models.Thread) (updatedThread *models.Thread, err error)
+	GetByID(ctx context.Context, id int) (thread *models.Thread, err error)
+	CreatePosts(ctx context.Context, threadId int, forumSlug string, post []*models.Post) (createdPosts []*models.Post, err error)
+	GetPosts(ctx context.Context, threadId int, params *models.PostsQueryParams) (posts []*models.Post, err error)
+	VoteBySlug(ctx context.Context, slug string, vote *models.Vote) (err error)
+	UpdateBySlug(ctx context.Context, thread *models.Thread) (updatedThread *models.Thread, err error)
+	VoteByID(ctx context.Context, threadId int, vote *models.Vote) (err error)
+	GetDeleted(ctx context.Context, slug string, id int) (thread *models.Thread, err error)
+	Delete(ctx context.Context, id int) (err error)
+	Restore(ctx context.Context, id int) (err error)
+	Archive(ctx context.Context, id int, archived bool) (thread *models.Thread, err error)
 }
 
 type ThreadRepository struct {
-	db *pgxpool.Pool
+	db     *DB
+	logger *slog.Logger
 }
 
-func CreateThreadRepository(db *pgxpool.Pool) IThreadRepository {
-	return &ThreadRepository{db: db}
+func CreateThreadRepository(db *DB, logger *slog.Logger) IThreadRepository {
+	return &ThreadRepository{db: db, logger: logger}
 }
 
-func (repo *ThreadRepository) GetBySlug(slug string) (thread *models.Thread, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ThreadQuery["GetBySlug"], slug)
+func (repo *ThreadRepository) GetBySlug(ctx context.Context, slug string) (thread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.GetBySlug", constants.ThreadQuery["GetBySlug"], slug)
 
 	thread = &models.Thread{}
-	err = row.Scan(&thread.ID, &thread.Slug, &thread.Author, &thread.Forum, &thread.Title, &thread.Msg, &thread.Created, &thread.Votes)
+	err = row.Scan(&thread.ID, &thread.Slug, &thread.Author, &thread.Forum, &thread.Title, &thread.Msg, &thread.Created, &thread.Votes, &thread.IsArchived)
 	return
 }
-func (repo *ThreadRepository) GetByID(id int) (thread *models.Thread, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ThreadQuery["GetByID"], id)
+func (repo *ThreadRepository) GetByID(ctx context.Context, id int) (thread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.GetByID", constants.ThreadQuery["GetByID"], id)
 
 	thread = &models.Thread{}
 	err = row.Scan(&thread.ID, &thread.Slug, &thread.Author, &thread.Forum,
-		&thread.Title, &thread.Msg, &thread.Created, &thread.Votes)
+		&thread.Title, &thread.Msg, &thread.Created, &thread.Votes, &thread.IsArchived)
 	return
 }
-func (repo *ThreadRepository) UpdateBySlug(thread *models.Thread) (updatedThread *models.Thread, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ThreadQuery["UpdateBySlug"], thread.Title, thread.Msg, thread.Slug)
+func (repo *ThreadRepository) UpdateBySlug(ctx context.Context, thread *models.Thread) (updatedThread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.UpdateBySlug", constants.ThreadQuery["UpdateBySlug"], thread.Title, thread.Msg, thread.Slug)
 	updatedThread = &models.Thread{}
 	err = row.Scan(&updatedThread.ID, &updatedThread.Slug, &updatedThread.Author, &updatedThread.Forum,
 		&updatedThread.Title, &updatedThread.Msg, &updatedThread.Created, &updatedThread.Votes)
 	return
 }
-func (repo *ThreadRepository) UpdateByID(thread *models.Thread) (updatedThread *models.Thread, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.ThreadQuery["UpdateByID"], thread.Title, thread.Msg, thread.ID)
+func (repo *ThreadRepository) UpdateByID(ctx context.Context, thread *models.Thread) (updatedThread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.UpdateByID", constants.ThreadQuery["UpdateByID"], thread.Title, thread.Msg, thread.ID)
 	updatedThread = &models.Thread{}
 	err = row.Scan(&updatedThread.ID, &updatedThread.Slug, &updatedThread.Author, &updatedThread.Forum,
 		&updatedThread.Title, &updatedThread.Msg, &updatedThread.Created, &updatedThread.Votes)
 	return
 }
 
-func (repo *ThreadRepository) VoteBySlug(slug string, vote *models.Vote) (err error) {
-	_, err = repo.db.Exec(context.Background(), constants.ThreadQuery["VoteBySlug"], vote.Username, slug, vote.Voice)
+func (repo *ThreadRepository) VoteBySlug(ctx context.Context, slug string, vote *models.Vote) (err error) {
+	_, err = repo.db.Exec(ctx, "ThreadQuery.VoteBySlug", constants.ThreadQuery["VoteBySlug"], vote.Username, slug, vote.Voice)
 	return
 }
 
-func (repo *ThreadRepository) VoteByID(id int, vote *models.Vote) (err error) {
-	_, err = repo.db.Exec(context.Background(), constants.ThreadQuery["VoteByID"], vote.Username, id, vote.Voice)
+func (repo *ThreadRepository) VoteByID(ctx context.Context, id int, vote *models.Vote) (err error) {
+	_, err = repo.db.Exec(ctx, "ThreadQuery.VoteByID", constants.ThreadQuery["VoteByID"], vote.Username, id, vote.Voice)
 	return
 }
 
-func (repo *ThreadRepository) CreatePostsBatch(threadId int, forumSlug string, posts []*models.Post) (createdPosts []*models.Post, err error) {
-	ctx := context.Background()
+func (repo *ThreadRepository) CreatePostsBatch(ctx context.Context, threadId int, forumSlug string, posts []*models.Post) (createdPosts []*models.Post, err error) {
 	tx, err := repo.db.Begin(ctx)
 	if err != nil {
 		return
@@ -80,7 +84,8 @@ func (repo *ThreadRepository) CreatePostsBatch(threadId int, forumSlug string, p
 		if err != nil {
 			trErr := tx.Rollback(ctx)
 			if trErr != nil {
-				err = trErr
+				repo.logger.WarnContext(ctx, "rollback failed",
+					slog.String("error", trErr.Error()), slog.String("cause", err.Error()))
 			}
 		} else {
 			trErr := tx.Commit(ctx)
@@ -97,7 +102,7 @@ func (repo *ThreadRepository) CreatePostsBatch(threadId int, forumSlug string, p
 		batch.Queue(constants.ThreadQuery["CreatePostsBatch"], post.Parent, post.Author, forumSlug, threadId, post.Message, createdTime)
 	}
 
-	batchRes := tx.SendBatch(ctx, batch)
+	batchRes := tx.SendBatch(ctx, "ThreadQuery.CreatePostsBatch", batch)
 	defer func() {
 		batchErr := batchRes.Close()
 		if batchErr != nil {
@@ -132,7 +137,7 @@ func (repo *ThreadRepository) CreatePostsBatch(threadId int, forumSlug string, p
 	return
 }
 
-func (repo *ThreadRepository) CreatePosts(threadId int, forumSlug string, posts []*models.Post) (createdPosts []*models.Post, err error) {
+func (repo *ThreadRepository) CreatePosts(ctx context.Context, threadId int, forumSlug string, posts []*models.Post) (createdPosts []*models.Post, err error) {
 	query := constants.ThreadQuery["PostsCreate"]
 
 	createdTime := time.Now()
@@ -151,7 +156,7 @@ func (repo *ThreadRepository) CreatePosts(threadId int, forumSlug string, posts
 	query = strings.TrimSuffix(query, ",")
 	query += constants.ThreadQuery["CreatePostsTwo"]
 
-	rows, err := repo.db.Query(context.Background(), query, values...)
+	rows, err := repo.db.Query(ctx, "ThreadQuery.PostsCreate", query, values...)
 	defer rows.Close()
 
 	if err != nil {
@@ -187,23 +192,23 @@ func (repo *ThreadRepository) CreatePosts(threadId int, forumSlug string, posts
 	return
 }
 
-func (repo *ThreadRepository) GetPosts(threadId int, params *models.PostsQueryParams) (posts []*models.Post, err error) {
+func (repo *ThreadRepository) GetPosts(ctx context.Context, threadId int, params *models.PostsQueryParams) (posts []*models.Post, err error) {
 	var rows pgx.Rows
 
 	if params.Since == 0 {
 		if params.Desc {
-			rows, err = repo.db.Query(context.Background(), constants.DescNoSincePostQuery[params.SortType],
+			rows, err = repo.db.Query(ctx, "DescNoSincePostQuery."+string(params.SortType), constants.DescNoSincePostQuery[params.SortType],
 				threadId, params.Limit)
 		} else {
-			rows, err = repo.db.Query(context.Background(), constants.AscNoSincePostQuery[params.SortType],
+			rows, err = repo.db.Query(ctx, "AscNoSincePostQuery."+string(params.SortType), constants.AscNoSincePostQuery[params.SortType],
 				threadId, params.Limit)
 		}
 	} else {
 		if params.Desc {
-			rows, err = repo.db.Query(context.Background(), constants.DescSincePostQuery[params.SortType],
+			rows, err = repo.db.Query(ctx, "DescSincePostQuery."+string(params.SortType), constants.DescSincePostQuery[params.SortType],
 				threadId, params.Since, params.Limit)
 		} else {
-			rows, err = repo.db.Query(context.Background(), constants.AscSincePostQuery[params.SortType],
+			rows, err = repo.db.Query(ctx, "AscSincePostQuery."+string(params.SortType), constants.AscSincePostQuery[params.SortType],
 				threadId, params.Since, params.Limit)
 		}
 	}
@@ -224,7 +229,8 @@ func (repo *ThreadRepository) GetPosts(threadId int, params *models.PostsQueryPa
 			&post.Thread,
 			&post.Created,
 			&post.IsEdited,
-			&post.Message)
+			&post.Message,
+			&post.IsDeleted)
 		if err != nil {
 			posts = nil
 			return
@@ -233,3 +239,35 @@ func (repo *ThreadRepository) GetPosts(threadId int, params *models.PostsQueryPa
 	}
 	return
 }
+
+// GetDeleted finds a deleted thread by id or, if set, by slug.
+func (repo *ThreadRepository) GetDeleted(ctx context.Context, slug string, id int) (thread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.GetDeleted", constants.ThreadQuery["GetDeleted"], id, slug)
+
+	thread = &models.Thread{}
+	err = row.Scan(&thread.ID, &thread.Slug, &thread.Author, &thread.Forum,
+		&thread.Title, &thread.Msg, &thread.Created, &thread.Votes, &thread.IsArchived)
+	return
+}
+
+// Delete hides a thread with its posts and votes, which are kept until the
+// thread is removed for good.
+func (repo *ThreadRepository) Delete(ctx context.Context, id int) (err error) {
+	_, err = repo.db.Exec(ctx, "ThreadQuery.Delete", constants.ThreadQuery["Delete"], id)
+	return
+}
+
+func (repo *ThreadRepository) Restore(ctx context.Context, id int) (err error) {
+	_, err = repo.db.Exec(ctx, "ThreadQuery.Restore", constants.ThreadQuery["Restore"], id)
+	return
+}
+
+// Archive makes a thread read-only, or writable again when archived is false.
+func (repo *ThreadRepository) Archive(ctx context.Context, id int, archived bool) (thread *models.Thread, err error) {
+	row := repo.db.QueryRow(ctx, "ThreadQuery.Archive", constants.ThreadQuery["Archive"], id, archived)
+
+	thread = &models.Thread{}
+	err = row.Scan(&thread.ID, &thread.Slug, &thread.Author, &thread.Forum,
+		&thread.Title, &thread.Msg, &thread.Created, &thread.Votes, &thread.IsArchived)
+	return
+}
@@ -4,28 +4,30 @@ import (
 	"context"
 	"db_project/app/models"
 	"db_project/utils/constants"
-	"github.com/jackc/pgx/v4/pgxpool"
+	"fmt"
+	"log/slog"
 )
 
 type IUserRepository interface {
-	Get(nickname *string) (user *models.User, err error)
-	Update(user *models.User) (updatedUser *models.User, err error)
-	GetUsersByUserNicknameOrEmail(user *models.User) (users []*models.User, err error)
-	All() (users *[]models.User, err error)
-	Create(user *models.User) (err error)
+	Get(ctx context.Context, nickname *string) (user *models.User, err error)
+	Update(ctx context.Context, user *models.User, passwordHash string) (updatedUser *models.User, err error)
+	GetUsersByUserNicknameOrEmail(ctx context.Context, user *models.User) (users []*models.User, err error)
+	All(ctx context.Context, params *models.UserListQueryParams) (users []*models.User, err error)
+	Create(ctx context.Context, user *models.User, passwordHash string) (err error)
 }
 
 type UserRepository struct {
-	db *pgxpool.Pool
+	db     *DB
+	logger *slog.Logger
 }
 
-func CreateUserRepository(db *pgxpool.Pool) IUserRepository {
-	return &UserRepository{db: db}
+func CreateUserRepository(db *DB, logger *slog.Logger) IUserRepository {
+	return &UserRepository{db: db, logger: logger}
 }
 
-func (repo *UserRepository) Get(nickname *string) (user *models.User, err error) {
+func (repo *UserRepository) Get(ctx context.Context, nickname *string) (user *models.User, err error) {
 	user = &models.User{}
-	row := repo.db.QueryRow(context.Background(), constants.UserQuery["Get"], *nickname)
+	row := repo.db.QueryRow(ctx, "UserQuery.Get", constants.UserQuery["Get"], *nickname)
 	err = row.Scan(
 		&user.Username,
 		&user.FullName,
@@ -34,17 +36,72 @@ func (repo *UserRepository) Get(nickname *string) (user *models.User, err error)
 	return
 }
 
-func (repo *UserRepository) All() (users *[]models.User, err error) {
+// All returns a page of users filtered as params asks, ordered by nickname
+// starting after params.Since or, for a fuzzy search, closest match first.
+// Their emails are left out.
+func (repo *UserRepository) All(ctx context.Context, params *models.UserListQueryParams) (users []*models.User, err error) {
+	query := constants.UserQuery["All"]
+	var args []interface{}
+	filter := func(name string, arg interface{}) {
+		args = append(args, arg)
+		query += fmt.Sprintf(constants.UserQuery[constants.SortType(name)], len(args))
+	}
+
+	if params.Since != "" {
+		if params.Desc {
+			filter("AllSinceDesc", params.Since)
+		} else {
+			filter("AllSince", params.Since)
+		}
+	}
+	if params.Query != "" {
+		if params.Match == "fuzzy" {
+			filter("AllFuzzy", params.Query)
+		} else {
+			filter("AllPrefix", likeEscaper.Replace(params.Query)+"%")
+		}
+	}
+	if !params.ActiveSince.IsZero() {
+		filter("AllActiveSince", params.ActiveSince)
+	}
+	switch {
+	case params.Query != "" && params.Match == "fuzzy":
+		filter("AllOrderFuzzy", params.Query)
+	case params.Desc:
+		query += constants.UserQuery["AllOrderDesc"]
+	default:
+		query += constants.UserQuery["AllOrder"]
+	}
+	filter("AllLimit", params.Limit)
+
+	rows, err := repo.db.Query(ctx, "UserQuery.All", query, args...)
+	defer rows.Close()
+	if err != nil {
+		return
+	}
+
+	users = make([]*models.User, 0)
+	for rows.Next() {
+		user := &models.User{}
+		if err = rows.Scan(&user.Username, &user.FullName, &user.About); err != nil {
+			users = nil
+			return
+		}
+		users = append(users, user)
+	}
+	err = rows.Err()
 	return
 }
 
-func (repo *UserRepository) Create(user *models.User) (err error) {
-	_, err = repo.db.Exec(context.Background(), constants.UserQuery["Create"], user.Username, user.FullName, user.About, user.Email)
+// Create stores user. An empty passwordHash leaves the user without a password.
+func (repo *UserRepository) Create(ctx context.Context, user *models.User, passwordHash string) (err error) {
+	_, err = repo.db.Exec(ctx, "UserQuery.Create", constants.UserQuery["Create"], user.Username, user.FullName, user.About, user.Email, passwordHash)
 	return
 }
 
-func (repo *UserRepository) Update(user *models.User) (updatedUser *models.User, err error) {
-	row := repo.db.QueryRow(context.Background(), constants.UserQuery["Update"], user.FullName, user.About, user.Email, user.Username)
+// Update changes the non-empty fields of user. An empty passwordHash keeps the password.
+func (repo *UserRepository) Update(ctx context.Context, user *models.User, passwordHash string) (updatedUser *models.User, err error) {
+	row := repo.db.QueryRow(ctx, "UserQuery.Update", constants.UserQuery["Update"], user.FullName, user.About, user.Email, user.Username, passwordHash)
 	updatedUser = &models.User{}
 	err = row.Scan(
 		&updatedUser.Username,
@@ -54,8 +111,8 @@ func (repo *UserRepository) Update(user *models.User) (updatedUser *models.User,
 	return
 }
 
-func (repo *UserRepository) GetUsersByUserNicknameOrEmail(user *models.User) (users []*models.User, err error) {
-	rows, err := repo.db.Query(context.Background(), constants.UserQuery["GetUsersByUserNOE"], user.Username, user.Email)
+func (repo *UserRepository) GetUsersByUserNicknameOrEmail(ctx context.Context, user *models.User) (users []*models.User, err error) {
+	rows, err := repo.db.Query(ctx, "UserQuery.GetUsersByUserNOE", constants.UserQuery["GetUsersByUserNOE"], user.Username, user.Email)
 	defer rows.Close()
 
 	if err != nil {
@@ -0,0 +1,38 @@
+package tracing
+
+import (
+	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryHook creates a client span for every named repository query.
+type QueryHook struct {
+	tracer trace.Tracer
+}
+
+func CreateQueryHook() *QueryHook {
+	return &QueryHook{tracer: otel.Tracer("db_project/app/repositories")}
+}
+
+func (h *QueryHook) QueryStart(ctx context.Context, name string) context.Context {
+	ctx, _ = h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			attribute.String("db.query.name", name),
+		))
+	return ctx
+}
+
+func (h *QueryHook) QueryEnd(ctx context.Context, name string, err error) {
+	span := trace.SpanFromContext(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,103 @@
+// Package tracing sets up OpenTelemetry tracing. Spans are created for every
+// route, usecase method and named repository query and exported through
+// OTLP/HTTP or written as JSON lines to stdout or a file for offline use.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"io"
+	"os"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+	ExporterOTLP   = "otlp"
+)
+
+type Options struct {
+	ServiceName string
+	Exporter    string
+	// File is the output of the file exporter.
+	File string
+	// Endpoint is the host:port of the OTLP/HTTP collector. When empty the
+	// standard OTEL_EXPORTER_OTLP_* environment variables are used.
+	Endpoint string
+	Insecure bool
+	// SampleRatio is the share of new traces that are recorded, 0..1.
+	// Traces started by a sampled upstream are always recorded.
+	SampleRatio float64
+}
+
+// Setup installs the global tracer provider and the W3C trace context
+// propagator. The returned function flushes pending spans and must be
+// called on shutdown.
+func Setup(ctx context.Context, options Options) (shutdown func(ctx context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	if options.Exporter == ExporterNone || options.Exporter == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporter sdktrace.SpanExporter
+	var output io.Closer
+	switch options.Exporter {
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var file *os.File
+		file, err = os.OpenFile(options.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("can't open trace file: %w", err)
+		}
+		output = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case ExporterOTLP:
+		var clientOptions []otlptracehttp.Option
+		if options.Endpoint != "" {
+			clientOptions = append(clientOptions, otlptracehttp.WithEndpoint(options.Endpoint))
+		}
+		if options.Insecure {
+			clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, clientOptions...)
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", options.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can't create %s trace exporter: %w", options.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL, semconv.ServiceName(options.ServiceName)))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(options.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if output != nil {
+			if closeErr := output.Close(); err == nil {
+				err = closeErr
+			}
+		}
+		return err
+	}, nil
+}
@@ -0,0 +1,151 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// lastUsedPrecision is how stale last_used may get before a request with the
+// key updates it, so busy bots don't write on every request.
+const lastUsedPrecision = time.Minute
+
+type IAPIKeyUseCase interface {
+	Create(ctx context.Context, nickname string, request *models.APIKeyRequest) (key *models.APIKey, err error)
+	List(ctx context.Context, nickname string) (keys []*models.APIKey, err error)
+	Revoke(ctx context.Context, nickname string, id int64) (err error)
+	// Verify resolves a key sent by a client, see middleware.Authenticate.
+	Verify(ctx context.Context, key string) (apiKey *auth.APIKey, err error)
+}
+
+type APIKeyUseCase struct {
+	apiKeyRepository repositories.IAPIKeyRepository
+	roleUseCase      IRoleUseCase
+	logger           *slog.Logger
+}
+
+func CreateAPIKeyUseCase(apiKeyRepository repositories.IAPIKeyRepository,
+	roleUseCase IRoleUseCase,
+	logger *slog.Logger) IAPIKeyUseCase {
+	return &APIKeyUseCase{apiKeyRepository: apiKeyRepository, roleUseCase: roleUseCase, logger: logger}
+}
+
+func (usecase *APIKeyUseCase) Create(ctx context.Context, nickname string, request *models.APIKeyRequest) (key *models.APIKey, err error) {
+	ctx, span := startSpan(ctx, "APIKeyUseCase.Create")
+	defer func() { endSpan(span, err) }()
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.ManageAPIKeys, "", nickname); err != nil {
+		return
+	}
+
+	if len(request.Scopes) == 0 {
+		request.Scopes = []string{"write"}
+	}
+	secret, prefix, hash, err := auth.NewAPIKey()
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "APIKeyUseCase.Create", err)
+		return
+	}
+
+	key, err = usecase.apiKeyRepository.Create(ctx, nickname, request, prefix, hash)
+	if err != nil {
+		key = nil
+		pgconErr, ok := err.(*pgconn.PgError)
+		switch {
+		case ok && pgconErr.SQLState() == errors.Err23502:
+			err = errors.NotFoundUser
+		case ok && pgconErr.SQLState() == errors.Err23503:
+			err = errors.NotFoundForum
+		default:
+			err = internalError(ctx, usecase.logger, "APIKeyUseCase.Create", err)
+		}
+		return
+	}
+	key.Key = secret
+
+	usecase.logger.InfoContext(ctx, "api key created", slog.String("nickname", nickname),
+		slog.Int64("api_key", key.ID), slog.String("by", auth.User(ctx)))
+	return
+}
+
+func (usecase *APIKeyUseCase) List(ctx context.Context, nickname string) (keys []*models.APIKey, err error) {
+	ctx, span := startSpan(ctx, "APIKeyUseCase.List")
+	defer func() { endSpan(span, err) }()
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.ManageAPIKeys, "", nickname); err != nil {
+		return
+	}
+
+	keys, err = usecase.apiKeyRepository.List(ctx, nickname)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "APIKeyUseCase.List", err)
+	}
+	return
+}
+
+func (usecase *APIKeyUseCase) Revoke(ctx context.Context, nickname string, id int64) (err error) {
+	ctx, span := startSpan(ctx, "APIKeyUseCase.Revoke")
+	defer func() { endSpan(span, err) }()
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.ManageAPIKeys, "", nickname); err != nil {
+		return
+	}
+
+	revoked, err := usecase.apiKeyRepository.Revoke(ctx, nickname, id)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "APIKeyUseCase.Revoke", err)
+		return
+	}
+	if !revoked {
+		err = errors.APIKeyNotFound
+		return
+	}
+
+	usecase.logger.InfoContext(ctx, "api key revoked", slog.String("nickname", nickname),
+		slog.Int64("api_key", id), slog.String("by", auth.User(ctx)))
+	return
+}
+
+func (usecase *APIKeyUseCase) Verify(ctx context.Context, key string) (apiKey *auth.APIKey, err error) {
+	ctx, span := startSpan(ctx, "APIKeyUseCase.Verify")
+	defer func() { endSpan(span, err) }()
+
+	if !strings.HasPrefix(key, auth.APIKeyPrefix) {
+		err = errors.InvalidToken
+		return
+	}
+
+	stored, err := usecase.apiKeyRepository.GetByHash(ctx, auth.HashToken(key))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.InvalidToken
+		} else {
+			err = internalError(ctx, usecase.logger, "APIKeyUseCase.Verify", err)
+		}
+		return
+	}
+
+	// A failed update of last_used isn't worth failing the request for.
+	if stored.LastUsed == nil || time.Since(*stored.LastUsed) > lastUsedPrecision {
+		if touchErr := usecase.apiKeyRepository.Touch(ctx, stored.ID); touchErr != nil {
+			usecase.logger.WarnContext(ctx, "can't update api key last_used",
+				slog.Int64("api_key", stored.ID), slog.String("error", touchErr.Error()))
+		}
+	}
+
+	apiKey = &auth.APIKey{ID: stored.ID, Nickname: stored.Nickname, ReadOnly: true, Forum: stored.Forum}
+	for _, scope := range stored.Scopes {
+		if scope == "write" {
+			apiKey.ReadOnly = false
+		}
+	}
+	return
+}
@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type IAuthUseCase interface {
+	Login(ctx context.Context, credentials *models.Credentials) (pair *models.TokenPair, err error)
+	Refresh(ctx context.Context, refreshToken string) (pair *models.TokenPair, err error)
+	Logout(ctx context.Context, refreshToken string) (err error)
+}
+
+type AuthUseCase struct {
+	authRepository repositories.IAuthRepository
+	tokens         *auth.Tokens
+	refreshTTL     time.Duration
+	logger         *slog.Logger
+}
+
+func CreateAuthUseCase(authRepository repositories.IAuthRepository,
+	tokens *auth.Tokens,
+	refreshTTL time.Duration,
+	logger *slog.Logger) IAuthUseCase {
+	return &AuthUseCase{authRepository: authRepository, tokens: tokens, refreshTTL: refreshTTL, logger: logger}
+}
+
+func (usecase *AuthUseCase) Login(ctx context.Context, credentials *models.Credentials) (pair *models.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthUseCase.Login")
+	defer func() { endSpan(span, err) }()
+
+	nickname, hash, err := usecase.authRepository.PasswordHash(ctx, credentials.Username)
+	if err != nil && err != pgx.ErrNoRows {
+		err = internalError(ctx, usecase.logger, "AuthUseCase.Login", err)
+		return
+	}
+
+	// Unknown users are checked against an empty hash, which takes as long as
+	// a wrong password and doesn't tell the two apart.
+	if !auth.CheckPassword(hash, credentials.Password) {
+		err = errors.InvalidCredentials
+		return
+	}
+
+	pair, err = usecase.issue(ctx, nickname)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "AuthUseCase.Login", err)
+	}
+	return
+}
+
+// Refresh exchanges a refresh token for a new pair. The old token can't be used again.
+func (usecase *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (pair *models.TokenPair, err error) {
+	ctx, span := startSpan(ctx, "AuthUseCase.Refresh")
+	defer func() { endSpan(span, err) }()
+
+	nickname, err := usecase.authRepository.UseRefreshToken(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.InvalidToken
+		} else {
+			err = internalError(ctx, usecase.logger, "AuthUseCase.Refresh", err)
+		}
+		return
+	}
+
+	pair, err = usecase.issue(ctx, nickname)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "AuthUseCase.Refresh", err)
+	}
+	return
+}
+
+// Logout revokes a refresh token. Access tokens stay valid until they expire.
+func (usecase *AuthUseCase) Logout(ctx context.Context, refreshToken string) (err error) {
+	ctx, span := startSpan(ctx, "AuthUseCase.Logout")
+	defer func() { endSpan(span, err) }()
+
+	err = usecase.authRepository.RevokeRefreshToken(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "AuthUseCase.Logout", err)
+	}
+	return
+}
+
+func (usecase *AuthUseCase) issue(ctx context.Context, nickname string) (pair *models.TokenPair, err error) {
+	refreshToken, refreshHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return
+	}
+	err = usecase.authRepository.CreateRefreshToken(ctx, refreshHash, nickname, time.Now().Add(usecase.refreshTTL))
+	if err != nil {
+		return
+	}
+
+	pair = &models.TokenPair{
+		AccessToken:  usecase.tokens.Issue(nickname),
+		TokenType:    "Bearer",
+		ExpiresIn:    int(usecase.tokens.TTL().Seconds()),
+		RefreshToken: refreshToken,
+	}
+	return
+}
+
+// checkActor rejects requests authenticated as someone other than nickname
+// and API keys restricted to a forum other than forum. Anonymous requests
+// pass: whether they are allowed at all is up to the auth middleware.
+func checkActor(ctx context.Context, nickname string, forum string) error {
+	actor := actorOf(ctx)
+	switch {
+	case actor.Nickname == "":
+		return nil
+	case !strings.EqualFold(actor.Nickname, nickname):
+		return errors.AuthorMismatch
+	case !actor.WritesTo(forum):
+		return errors.APIKeyForum
+	}
+	return nil
+}
+
+// actorOf returns the actor of ctx as a member; roles are looked up by
+// RoleUseCase.Authorize when they matter.
+func actorOf(ctx context.Context) permissions.Actor {
+	actor := permissions.Actor{Nickname: auth.User(ctx), Role: permissions.Member}
+	if key := auth.Key(ctx); key != nil {
+		actor.APIKey = true
+		actor.KeyForum = key.Forum
+	}
+	return actor
+}
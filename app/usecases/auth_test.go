@@ -0,0 +1,154 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"github.com/jackc/pgx/v4"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// memoryAuth keeps password hashes and refresh tokens like the auth repository.
+type memoryAuth struct {
+	hashes  map[string]string
+	refresh map[string]*storedRefresh
+}
+
+type storedRefresh struct {
+	nickname  string
+	expiresAt time.Time
+	revoked   bool
+}
+
+func (repo *memoryAuth) PasswordHash(ctx context.Context, nickname string) (string, string, error) {
+	hash, ok := repo.hashes[nickname]
+	if !ok {
+		return "", "", pgx.ErrNoRows
+	}
+	return nickname, hash, nil
+}
+
+func (repo *memoryAuth) SetPassword(ctx context.Context, nickname string, hash string) (string, error) {
+	repo.hashes[nickname] = hash
+	return nickname, nil
+}
+
+func (repo *memoryAuth) CreateRefreshToken(ctx context.Context, hash string, nickname string, expiresAt time.Time) error {
+	repo.refresh[hash] = &storedRefresh{nickname: nickname, expiresAt: expiresAt}
+	return nil
+}
+
+func (repo *memoryAuth) UseRefreshToken(ctx context.Context, hash string) (string, error) {
+	token, ok := repo.refresh[hash]
+	if !ok || token.revoked || !time.Now().Before(token.expiresAt) {
+		return "", pgx.ErrNoRows
+	}
+	token.revoked = true
+	return token.nickname, nil
+}
+
+func (repo *memoryAuth) RevokeRefreshToken(ctx context.Context, hash string) error {
+	if token, ok := repo.refresh[hash]; ok {
+		token.revoked = true
+	}
+	return nil
+}
+
+func createAuth(t *testing.T, refreshTTL time.Duration) (IAuthUseCase, *auth.Tokens) {
+	t.Helper()
+	hash, err := auth.HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+	repo := &memoryAuth{hashes: map[string]string{"alice": hash, "legacy": ""}, refresh: make(map[string]*storedRefresh)}
+	tokens := auth.CreateTokens([]byte("0123456789abcdef0123456789abcdef"), time.Minute)
+	return CreateAuthUseCase(repo, tokens, refreshTTL, slog.New(slog.NewTextHandler(io.Discard, nil))), tokens
+}
+
+func TestLogin(t *testing.T) {
+	tests := []struct {
+		name        string
+		credentials models.Credentials
+		want        error
+	}{
+		{"right password", models.Credentials{Username: "alice", Password: "correct horse"}, nil},
+		{"wrong password", models.Credentials{Username: "alice", Password: "wrong horse"}, errors.InvalidCredentials},
+		{"unknown user", models.Credentials{Username: "mallory", Password: "correct horse"}, errors.InvalidCredentials},
+		{"user without password", models.Credentials{Username: "legacy", Password: ""}, errors.InvalidCredentials},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usecase, tokens := createAuth(t, time.Hour)
+			pair, err := usecase.Login(context.Background(), &test.credentials)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("Login() error = %v, want %v", err, test.want)
+			}
+			if test.want != nil {
+				return
+			}
+			if nickname, err := tokens.Parse(pair.AccessToken); err != nil || nickname != "alice" {
+				t.Errorf("access token is for %q, %v, want alice", nickname, err)
+			}
+		})
+	}
+}
+
+func keepToken(usecase IAuthUseCase, token string) string {
+	return token
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name       string
+		refreshTTL time.Duration
+		// before runs between login and the refresh under test and returns
+		// the refresh token to use.
+		before func(usecase IAuthUseCase, token string) string
+		want   error
+	}{
+		{"fresh token", time.Hour, keepToken, nil},
+		{"reused token", time.Hour, func(usecase IAuthUseCase, token string) string {
+			_, _ = usecase.Refresh(context.Background(), token)
+			return token
+		}, errors.InvalidToken},
+		{"after logout", time.Hour, func(usecase IAuthUseCase, token string) string {
+			_ = usecase.Logout(context.Background(), token)
+			return token
+		}, errors.InvalidToken},
+		{"expired token", -time.Second, keepToken, errors.InvalidToken},
+		{"unknown token", time.Hour, func(IAuthUseCase, string) string {
+			token, _, _ := auth.NewRefreshToken()
+			return token
+		}, errors.InvalidToken},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			usecase, tokens := createAuth(t, test.refreshTTL)
+			pair, err := usecase.Login(context.Background(), &models.Credentials{Username: "alice", Password: "correct horse"})
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			token := test.before(usecase, pair.RefreshToken)
+
+			refreshed, err := usecase.Refresh(context.Background(), token)
+			if !errors.Is(err, test.want) {
+				t.Fatalf("Refresh() error = %v, want %v", err, test.want)
+			}
+			if test.want != nil {
+				return
+			}
+			if refreshed.RefreshToken == token {
+				t.Error("Refresh() returned the same refresh token, want it rotated")
+			}
+			if nickname, err := tokens.Parse(refreshed.AccessToken); err != nil || nickname != "alice" {
+				t.Errorf("access token is for %q, %v, want alice", nickname, err)
+			}
+		})
+	}
+}
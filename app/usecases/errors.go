@@ -0,0 +1,23 @@
+package usecases
+
+import (
+	"context"
+	"db_project/utils/errors"
+	"log/slog"
+)
+
+// internalError logs a repository error that has no specific meaning for the
+// client before it is replaced by the generic error the client receives.
+func internalError(ctx context.Context, logger *slog.Logger, operation string, err error) *errors.Error {
+	mapped := errors.Internal(err)
+
+	level := slog.LevelError
+	if !errors.Is(mapped, errors.ServerInternal) {
+		level = slog.LevelWarn
+	}
+	logger.LogAttrs(ctx, level, operation+" failed",
+		slog.String("error", err.Error()),
+		slog.Int("status", mapped.Code()))
+
+	return mapped
+}
@@ -1,33 +1,59 @@
 package usecases
 
 import (
+	"context"
 	"db_project/app/models"
 	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/constants"
 	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"encoding/base64"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type IForumUseCase interface {
-	Create(forum *models.Forum) (createdForum *models.Forum, err error)
-	Get(slug string) (forum *models.Forum, err error)
-	CreateThread(thread *models.Thread) (createdThread *models.Thread, err error)
-	GetThreads(slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error)
-	GetUsers(slug string, params *models.ForumUserQueryParams) (users []*models.User, err error)
+	Create(ctx context.Context, forum *models.Forum) (createdForum *models.Forum, err error)
+	Get(ctx context.Context, slug string) (forum *models.Forum, err error)
+	CreateThread(ctx context.Context, thread *models.Thread) (createdThread *models.Thread, err error)
+	GetThreads(ctx context.Context, slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error)
+	GetUsers(ctx context.Context, slug string, params *models.ForumUserQueryParams) (users []*models.User, err error)
+	Update(ctx context.Context, slug string, forum *models.ForumUpdate) (updatedForum *models.Forum, err error)
+	Delete(ctx context.Context, slug string) (report *models.ClearReport, err error)
+	List(ctx context.Context, params *models.ForumListQueryParams) (list *models.ForumList, err error)
 }
 
 type ForumUseCase struct {
-	forumRepository  repositories.IForumRepository
-	threadRepository repositories.IThreadRepository
+	forumRepository   repositories.IForumRepository
+	threadRepository  repositories.IThreadRepository
+	serviceRepository repositories.IServiceRepository
+	roleUseCase       IRoleUseCase
+	logger            *slog.Logger
 }
 
 func CreateForumUseCase(forumRepository repositories.IForumRepository,
-	threadRepository repositories.IThreadRepository) IForumUseCase {
-	return &ForumUseCase{forumRepository: forumRepository, threadRepository: threadRepository}
+	threadRepository repositories.IThreadRepository,
+	serviceRepository repositories.IServiceRepository,
+	roleUseCase IRoleUseCase,
+	logger *slog.Logger) IForumUseCase {
+	return &ForumUseCase{forumRepository: forumRepository, threadRepository: threadRepository,
+		serviceRepository: serviceRepository, roleUseCase: roleUseCase, logger: logger}
 }
 
-func (usecase *ForumUseCase) Create(forum *models.Forum) (createdForum *models.Forum, err error) {
-	createdForum, err = usecase.forumRepository.Create(forum)
+func (usecase *ForumUseCase) Create(ctx context.Context, forum *models.Forum) (createdForum *models.Forum, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.Create")
+	defer func() { endSpan(span, err) }()
+
+	if err = checkActor(ctx, forum.User, forum.Slug); err != nil {
+		return
+	}
+
+	createdForum, err = usecase.forumRepository.Create(ctx, forum)
 
 	if err != nil {
 		pgconErr, ok := err.(*pgconn.PgError)
@@ -39,39 +65,51 @@ func (usecase *ForumUseCase) Create(forum *models.Forum) (createdForum *models.F
 				return
 
 			case errors.Err23505:
-				createdForum, err = usecase.forumRepository.Get(forum.Slug)
+				createdForum, err = usecase.forumRepository.Get(ctx, forum.Slug)
 				if err != nil {
-					err = errors.ServerInternal
+					err = internalError(ctx, usecase.logger, "ForumUseCase.Create", err)
 					return
 				}
 				err = errors.ForumAlreadyExists
 				return
 
 			default:
-				err = errors.ServerInternal
+				err = internalError(ctx, usecase.logger, "ForumUseCase.Create", err)
 			}
+		} else {
+			err = internalError(ctx, usecase.logger, "ForumUseCase.Create", err)
 		}
 	}
 
 	return
 }
 
-func (usecase *ForumUseCase) Get(slug string) (forum *models.Forum, err error) {
-	forum, err = usecase.forumRepository.Get(slug)
+func (usecase *ForumUseCase) Get(ctx context.Context, slug string) (forum *models.Forum, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.Get")
+	defer func() { endSpan(span, err) }()
+
+	forum, err = usecase.forumRepository.Get(ctx, slug)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			err = errors.NotFoundForum
 		} else {
-			err = errors.ServerInternal
+			err = internalError(ctx, usecase.logger, "ForumUseCase.Get", err)
 		}
 	}
 
 	return
 }
 
-func (usecase *ForumUseCase) CreateThread(thread *models.Thread) (createdThread *models.Thread, err error) {
-	createdThread, err = usecase.forumRepository.CreateThread(thread)
+func (usecase *ForumUseCase) CreateThread(ctx context.Context, thread *models.Thread) (createdThread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.CreateThread")
+	defer func() { endSpan(span, err) }()
+
+	if err = checkActor(ctx, thread.Author, thread.Forum); err != nil {
+		return
+	}
+
+	createdThread, err = usecase.forumRepository.CreateThread(ctx, thread)
 
 	if err != nil {
 		pgconErr, ok := err.(*pgconn.PgError)
@@ -83,32 +121,37 @@ func (usecase *ForumUseCase) CreateThread(thread *models.Thread) (createdThread
 				return
 
 			case errors.Err23505:
-				createdThread, err = usecase.threadRepository.GetBySlug(thread.Slug)
+				createdThread, err = usecase.threadRepository.GetBySlug(ctx, thread.Slug)
 				if err != nil {
-					err = errors.ServerInternal
+					err = internalError(ctx, usecase.logger, "ForumUseCase.CreateThread", err)
 					return
 				}
 				err = errors.ThreadAlreadyExists
 				return
 
 			default:
-				err = errors.ServerInternal
+				err = internalError(ctx, usecase.logger, "ForumUseCase.CreateThread", err)
 			}
+		} else {
+			err = internalError(ctx, usecase.logger, "ForumUseCase.CreateThread", err)
 		}
 	}
 
 	return
 }
 
-func (usecase *ForumUseCase) GetThreads(slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error) {
-	threads, err = usecase.forumRepository.GetThreads(slug, params)
+func (usecase *ForumUseCase) GetThreads(ctx context.Context, slug string, params *models.ForumQueryParams) (threads []*models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.GetThreads")
+	defer func() { endSpan(span, err) }()
+
+	threads, err = usecase.forumRepository.GetThreads(ctx, slug, params)
 	if err != nil {
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "ForumUseCase.GetThreads", err)
 		return
 	}
 
 	if len(threads) == 0 {
-		_, err = usecase.Get(slug)
+		_, err = usecase.Get(ctx, slug)
 		if err != nil {
 			return
 		}
@@ -117,16 +160,19 @@ func (usecase *ForumUseCase) GetThreads(slug string, params *models.ForumQueryPa
 	return
 }
 
-func (usecase *ForumUseCase) GetUsers(slug string, params *models.ForumUserQueryParams) (users []*models.User, err error) {
-	users, err = usecase.forumRepository.GetUsers(slug, params)
+func (usecase *ForumUseCase) GetUsers(ctx context.Context, slug string, params *models.ForumUserQueryParams) (users []*models.User, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.GetUsers")
+	defer func() { endSpan(span, err) }()
+
+	users, err = usecase.forumRepository.GetUsers(ctx, slug, params)
 
 	if err != nil {
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "ForumUseCase.GetUsers", err)
 		return
 	}
 
 	if len(users) == 0 {
-		if _, err = usecase.Get(slug); err != nil {
+		if _, err = usecase.Get(ctx, slug); err != nil {
 			return
 		}
 		return
@@ -134,3 +180,134 @@ func (usecase *ForumUseCase) GetUsers(slug string, params *models.ForumUserQuery
 
 	return
 }
+
+// Update changes the title, description or owner of a forum, and renames it
+// when forum.Slug is set. Only the owner and admins may.
+func (usecase *ForumUseCase) Update(ctx context.Context, slug string, forum *models.ForumUpdate) (updatedForum *models.Forum, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.Update")
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.Get(ctx, slug)
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.EditForum, current.Slug, ""); err != nil {
+		return
+	}
+
+	updatedForum, err = usecase.forumRepository.Update(ctx, current.Slug, forum)
+	if err != nil {
+		updatedForum = nil
+		pgconErr, ok := err.(*pgconn.PgError)
+		switch {
+		case err == pgx.ErrNoRows:
+			err = errors.NotFoundForum
+		case ok && pgconErr.SQLState() == errors.Err23502:
+			err = errors.NotFoundForumUser
+		case ok && pgconErr.SQLState() == errors.Err23505:
+			err = errors.ForumSlugConflict
+		default:
+			err = internalError(ctx, usecase.logger, "ForumUseCase.Update", err)
+		}
+		return
+	}
+
+	if updatedForum.Slug != current.Slug {
+		usecase.logger.InfoContext(ctx, "forum renamed", slog.String("forum", current.Slug),
+			slog.String("slug", updatedForum.Slug), slog.String("by", auth.User(ctx)))
+	}
+	return
+}
+
+// Delete removes a forum with everything in it, see
+// repositories.IServiceRepository.ClearForum.
+func (usecase *ForumUseCase) Delete(ctx context.Context, slug string) (report *models.ClearReport, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.Delete")
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.Get(ctx, slug)
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.DeleteForum, current.Slug, ""); err != nil {
+		return
+	}
+
+	report, err = usecase.serviceRepository.ClearForum(ctx, current.Slug, false)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.NotFoundForum
+			return
+		}
+		err = internalError(ctx, usecase.logger, "ForumUseCase.Delete", err)
+		return
+	}
+
+	usecase.logger.InfoContext(ctx, "forum deleted", slog.String("forum", current.Slug), slog.String("by", auth.User(ctx)),
+		slog.Int("threads", report.Thread), slog.Int("posts", report.Post))
+	return
+}
+
+// List returns a page of forums. Pages are chained by an opaque cursor
+// holding the sort value and the slug of the last forum of a page, so
+// forums created meanwhile neither repeat nor shift the pages.
+func (usecase *ForumUseCase) List(ctx context.Context, params *models.ForumListQueryParams) (list *models.ForumList, err error) {
+	ctx, span := startSpan(ctx, "ForumUseCase.List")
+	defer func() { endSpan(span, err) }()
+
+	var after, afterSlug string
+	if params.Cursor != "" {
+		var ok bool
+		if after, afterSlug, ok = decodeForumCursor(params.Cursor, params.Sort); !ok {
+			err = errors.BadRequest.WithDetails("invalid_cursor")
+			return
+		}
+	}
+
+	forums, err := usecase.forumRepository.List(ctx, params, after, afterSlug)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "ForumUseCase.List", err)
+		return
+	}
+
+	list = &models.ForumList{Forums: forums}
+	if len(forums) == params.Limit {
+		list.Next = encodeForumCursor(forums[len(forums)-1], params.Sort)
+	}
+	return
+}
+
+func encodeForumCursor(forum *models.Forum, sort constants.SortType) string {
+	var value string
+	switch sort {
+	case constants.SortCreated:
+		value = forum.Created.Format(time.RFC3339Nano)
+	case constants.SortThreads:
+		value = strconv.Itoa(forum.Threads)
+	case constants.SortPosts:
+		value = strconv.Itoa(forum.Posts)
+	default:
+		value = forum.Title
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(string(sort) + "\n" + forum.Slug + "\n" + value))
+}
+
+// decodeForumCursor rejects cursors that weren't made for sort or whose value
+// doesn't parse, which would fail the query instead.
+func decodeForumCursor(cursor string, sort constants.SortType) (value string, slug string, ok bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return
+	}
+	parts := strings.SplitN(string(raw), "\n", 3)
+	if len(parts) != 3 || constants.SortType(parts[0]) != sort || parts[1] == "" {
+		return
+	}
+	switch sort {
+	case constants.SortCreated:
+		_, err = time.Parse(time.RFC3339Nano, parts[2])
+	case constants.SortThreads, constants.SortPosts:
+		_, err = strconv.Atoi(parts[2])
+	}
+	return parts[2], parts[1], err == nil
+}
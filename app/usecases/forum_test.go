@@ -0,0 +1,62 @@
+package usecases
+
+import (
+	"db_project/app/models"
+	"db_project/utils/constants"
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func TestForumCursorRoundTrip(t *testing.T) {
+	created := time.Date(2026, 10, 18, 9, 30, 15, 123456789, time.FixedZone("MSK", 3*60*60))
+	forum := &models.Forum{Slug: "pirates", Title: "Pirates\nof the Caribbean", Threads: 42, Posts: 1337, Created: created}
+
+	tests := []struct {
+		sort  constants.SortType
+		value string
+	}{
+		{constants.SortTitle, "Pirates\nof the Caribbean"},
+		{constants.SortCreated, "2026-10-18T09:30:15.123456789+03:00"},
+		{constants.SortThreads, "42"},
+		{constants.SortPosts, "1337"},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.sort), func(t *testing.T) {
+			cursor := encodeForumCursor(forum, test.sort)
+			value, slug, ok := decodeForumCursor(cursor, test.sort)
+			if !ok || value != test.value || slug != forum.Slug {
+				t.Errorf("decodeForumCursor(encodeForumCursor()) = %q, %q, %v, want %q, %q, true",
+					value, slug, ok, test.value, forum.Slug)
+			}
+		})
+	}
+}
+
+func TestDecodeForumCursorRejects(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   constants.SortType
+	}{
+		{"not base64", "not a cursor!", constants.SortTitle},
+		{"missing value", encode("title\npirates"), constants.SortTitle},
+		{"empty slug", encode("title\n\nPirates"), constants.SortTitle},
+		{"other sort", encode("title\npirates\nPirates"), constants.SortPosts},
+		{"bad count", encode("threads\npirates\nmany"), constants.SortThreads},
+		{"bad time", encode("created\npirates\nyesterday"), constants.SortCreated},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if value, slug, ok := decodeForumCursor(test.cursor, test.sort); ok {
+				t.Errorf("decodeForumCursor(%q, %s) = %q, %q, true, want it rejected", test.cursor, test.sort, value, slug)
+			}
+		})
+	}
+}
@@ -1,11 +1,15 @@
 package usecases
 
 import (
+	"context"
 	"db_project/app/models"
 	"db_project/app/repositories"
 	"db_project/utils/constants"
 	"db_project/utils/errors"
+	"db_project/utils/permissions"
 	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
 	"strconv"
 )
 
@@ -14,60 +18,72 @@ type PostUseCase struct {
 	forumUseCase   IForumUseCase
 	userUseCase    IUserUseCase
 	threadUseCase  IThreadUseCase
+	roleUseCase    IRoleUseCase
+	logger         *slog.Logger
 }
 
 type IPostUseCase interface {
-	Get(id int, details []string) (postDetailed *models.ParamsPost, err error)
-	Update(post *models.Post) (updatedPost *models.Post, err error)
+	Get(ctx context.Context, id int, details []string) (postDetailed *models.ParamsPost, err error)
+	Update(ctx context.Context, post *models.Post) (updatedPost *models.Post, err error)
+	Delete(ctx context.Context, id int, hard bool) (deletedPost *models.Post, err error)
+	Restore(ctx context.Context, id int) (restoredPost *models.Post, err error)
 }
 
 func CreatePostUseCase(postRepository repositories.IPostRepository,
 	forumUseCase IForumUseCase,
 	userUseCase IUserUseCase,
-	threadUseCase IThreadUseCase) IPostUseCase {
+	threadUseCase IThreadUseCase,
+	roleUseCase IRoleUseCase,
+	logger *slog.Logger) IPostUseCase {
 	return &PostUseCase{
 		postRepository: postRepository,
 		forumUseCase:   forumUseCase,
 		userUseCase:    userUseCase,
 		threadUseCase:  threadUseCase,
+		roleUseCase:    roleUseCase,
+		logger:         logger,
 	}
 }
 
-func (usecase *PostUseCase) Get(id int, details []string) (postDetailed *models.ParamsPost, err error) {
+func (usecase *PostUseCase) Get(ctx context.Context, id int, details []string) (postDetailed *models.ParamsPost, err error) {
+	ctx, span := startSpan(ctx, "PostUseCase.Get",
+		attribute.Int("post.id", id), attribute.StringSlice("post.related", details))
+	defer func() { endSpan(span, err) }()
+
 	postDetailed = &models.ParamsPost{}
-	postDetailed.Post, err = usecase.postRepository.Get(id)
+	postDetailed.Post, err = usecase.postRepository.GetVisible(ctx, id)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			err = errors.PostNotFound
 			return
 		}
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "PostUseCase.Get", err)
 		return
 	}
 
 	for _, detailType := range details {
 		switch detailType {
 		case constants.PostUser:
-			postDetailed.Author, err = usecase.userUseCase.Get(&postDetailed.Post.Author)
+			postDetailed.Author, err = usecase.userUseCase.Get(ctx, &postDetailed.Post.Author)
 			if err != nil {
 				postDetailed = nil
 				return
 			}
 		case constants.PostThread:
-			postDetailed.Thread, err = usecase.threadUseCase.Get(strconv.Itoa(postDetailed.Post.Thread))
+			postDetailed.Thread, err = usecase.threadUseCase.Get(ctx, strconv.Itoa(postDetailed.Post.Thread))
 			if err != nil {
 				postDetailed = nil
 				return
 			}
 		case constants.PostForum:
-			postDetailed.Forum, err = usecase.forumUseCase.Get(postDetailed.Post.Forum)
+			postDetailed.Forum, err = usecase.forumUseCase.Get(ctx, postDetailed.Post.Forum)
 			if err != nil {
 				postDetailed = nil
 				return
 			}
 		default:
 			postDetailed = nil
-			err = errors.BadRequest.SetTextDetails("неверные query параметры")
+			err = errors.BadRequest.WithDetails("invalid_query")
 			return
 		}
 	}
@@ -75,17 +91,122 @@ func (usecase *PostUseCase) Get(id int, details []string) (postDetailed *models.
 	return
 }
 
-func (usecase *PostUseCase) Update(post *models.Post) (updatedPost *models.Post, err error) {
-	updatedPost, err = usecase.postRepository.Update(post)
+func (usecase *PostUseCase) Update(ctx context.Context, post *models.Post) (updatedPost *models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostUseCase.Update")
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.get(ctx, "PostUseCase.Update", post.ID)
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.EditPost, current.Forum, current.Author); err != nil {
+		return
+	}
+	if current.IsDeleted {
+		err = errors.PostDeleted
+		return
+	}
+
+	updatedPost, err = usecase.postRepository.Update(ctx, post)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			err = errors.PostNotFound
+			err = usecase.updateRefused(ctx, post.ID)
 			return
 		}
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "PostUseCase.Update", err)
 		return
 	}
 
 	return
 }
+
+// Delete hides a post, leaving a placeholder so its replies keep their place
+// in the tree. A hard delete, for moderators, removes a post without replies.
+func (usecase *PostUseCase) Delete(ctx context.Context, id int, hard bool) (deletedPost *models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostUseCase.Delete", attribute.Int("post.id", id), attribute.Bool("post.hard", hard))
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.get(ctx, "PostUseCase.Delete", id)
+	if err != nil {
+		return
+	}
+
+	if hard {
+		if err = usecase.roleUseCase.Authorize(ctx, permissions.PurgePost, current.Forum, current.Author); err != nil {
+			return
+		}
+		var hasReplies bool
+		hasReplies, err = usecase.postRepository.Purge(ctx, id)
+		switch {
+		case err == pgx.ErrNoRows:
+			err = errors.PostNotFound
+		case err != nil:
+			err = internalError(ctx, usecase.logger, "PostUseCase.Delete", err)
+		case hasReplies:
+			err = errors.PostHasReplies
+		}
+		return
+	}
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.DeletePost, current.Forum, current.Author); err != nil {
+		return
+	}
+	if err = usecase.postRepository.Delete(ctx, id); err != nil {
+		err = internalError(ctx, usecase.logger, "PostUseCase.Delete", err)
+		return
+	}
+
+	deletedPost = current
+	deletedPost.IsDeleted = true
+	deletedPost.Message = ""
+	return
+}
+
+func (usecase *PostUseCase) Restore(ctx context.Context, id int) (restoredPost *models.Post, err error) {
+	ctx, span := startSpan(ctx, "PostUseCase.Restore", attribute.Int("post.id", id))
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.get(ctx, "PostUseCase.Restore", id)
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.RestorePost, current.Forum, current.Author); err != nil {
+		return
+	}
+	if err = usecase.postRepository.Restore(ctx, id); err != nil {
+		err = internalError(ctx, usecase.logger, "PostUseCase.Restore", err)
+		return
+	}
+
+	return usecase.get(ctx, "PostUseCase.Restore", id)
+}
+
+func (usecase *PostUseCase) get(ctx context.Context, op string, id int) (post *models.Post, err error) {
+	post, err = usecase.postRepository.Get(ctx, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.PostNotFound
+			return
+		}
+		err = internalError(ctx, usecase.logger, op, err)
+	}
+	return
+}
+
+// updateRefused tells why a post couldn't be updated: it is deleted, its
+// thread is archived or it doesn't exist.
+func (usecase *PostUseCase) updateRefused(ctx context.Context, id int) error {
+	post, err := usecase.postRepository.Get(ctx, id)
+	switch {
+	case err != nil:
+		return errors.PostNotFound
+	case post.IsDeleted:
+		return errors.PostDeleted
+	}
+	thread, err := usecase.threadUseCase.Get(ctx, strconv.Itoa(post.Thread))
+	if err == nil && thread.IsArchived {
+		return errors.ThreadArchived
+	}
+	return errors.PostNotFound
+}
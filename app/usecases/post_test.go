@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/errors"
+	"github.com/jackc/pgx/v4"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// editPosts holds a single post by alice and records updates.
+type editPosts struct {
+	repositories.IPostRepository
+	updated bool
+}
+
+func (repo *editPosts) Get(ctx context.Context, id int) (*models.Post, error) {
+	return &models.Post{ID: id, Author: "alice", Forum: "pirates", Thread: 1, Message: "Ahoy"}, nil
+}
+
+func (repo *editPosts) Update(ctx context.Context, post *models.Post) (*models.Post, error) {
+	repo.updated = true
+	return post, nil
+}
+
+func TestPostUpdateAuthorization(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, test := range editTests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &editPosts{}
+			roles := CreateRoleUseCase(memberRoles{}, nil, test.anonymousEdits, logger)
+			usecase := CreatePostUseCase(repo, nil, nil, nil, roles, logger)
+
+			_, err := usecase.Update(editContext(test.user), &models.Post{ID: 7, Message: "Ahoy!"})
+			if !errors.Is(err, test.want) {
+				t.Errorf("Update() error = %v, want %v", err, test.want)
+			}
+			if repo.updated != (test.want == nil) {
+				t.Errorf("post updated = %v, want %v", repo.updated, test.want == nil)
+			}
+		})
+	}
+}
+
+// deletedThreadPosts holds a post whose thread is deleted: it can be read
+// for moderation but not shown.
+type deletedThreadPosts struct {
+	repositories.IPostRepository
+}
+
+func (deletedThreadPosts) Get(ctx context.Context, id int) (*models.Post, error) {
+	return &models.Post{ID: id, Author: "alice", Forum: "pirates", Thread: 1, Message: "Ahoy"}, nil
+}
+
+func (deletedThreadPosts) GetVisible(ctx context.Context, id int) (*models.Post, error) {
+	return nil, pgx.ErrNoRows
+}
+
+func TestPostGetHidesDeletedThreads(t *testing.T) {
+	usecase := CreatePostUseCase(deletedThreadPosts{}, nil, nil, nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if post, err := usecase.Get(context.Background(), 7, []string{"thread"}); !errors.Is(err, errors.PostNotFound) {
+		t.Errorf("Get() = %+v, %v, want %v", post, err, errors.PostNotFound)
+	}
+}
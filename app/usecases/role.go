@@ -0,0 +1,162 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+)
+
+type IRoleUseCase interface {
+	// Authorize checks that the user of ctx may perform action on a resource
+	// of forum written by author. Either may be "" when it doesn't apply.
+	Authorize(ctx context.Context, action permissions.Action, forum string, author string) (err error)
+	Moderators(ctx context.Context, forum string) (moderators []*models.Moderator, err error)
+	GrantModerator(ctx context.Context, forum string, nickname string) (moderator *models.Moderator, err error)
+	RevokeModerator(ctx context.Context, forum string, nickname string) (err error)
+}
+
+type RoleUseCase struct {
+	roleRepository  repositories.IRoleRepository
+	forumRepository repositories.IForumRepository
+	anonymousEdits  bool
+	logger          *slog.Logger
+}
+
+// CreateRoleUseCase takes the forum repository rather than the forum usecase,
+// which needs the roles itself. anonymousEdits is auth.anonymous_edits.
+func CreateRoleUseCase(roleRepository repositories.IRoleRepository,
+	forumRepository repositories.IForumRepository,
+	anonymousEdits bool,
+	logger *slog.Logger) IRoleUseCase {
+	return &RoleUseCase{roleRepository: roleRepository, forumRepository: forumRepository, anonymousEdits: anonymousEdits, logger: logger}
+}
+
+func (usecase *RoleUseCase) Authorize(ctx context.Context, action permissions.Action, forum string, author string) (err error) {
+	ctx, span := startSpan(ctx, "RoleUseCase.Authorize")
+	defer func() { endSpan(span, err) }()
+
+	// Anonymous requests and authors acting on their own resources are
+	// decided without looking the role up.
+	actor := actorOf(ctx)
+	actor.AnonymousEdits = usecase.anonymousEdits
+	if err = permissions.Check(actor, action, forum, author); err == nil || !errors.Is(err, permissions.ErrForbidden) {
+		err = permissionError(err)
+		return
+	}
+
+	role, err := usecase.roleRepository.Role(ctx, actor.Nickname, forum)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "RoleUseCase.Authorize", err)
+		return
+	}
+	actor.Role = permissions.Role(role)
+	err = permissionError(permissions.Check(actor, action, forum, author))
+	return
+}
+
+func (usecase *RoleUseCase) Moderators(ctx context.Context, forum string) (moderators []*models.Moderator, err error) {
+	ctx, span := startSpan(ctx, "RoleUseCase.Moderators")
+	defer func() { endSpan(span, err) }()
+
+	found, err := usecase.forum(ctx, "RoleUseCase.Moderators", forum)
+	if err != nil {
+		return
+	}
+
+	moderators, err = usecase.roleRepository.Moderators(ctx, found.Slug)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "RoleUseCase.Moderators", err)
+	}
+	return
+}
+
+func (usecase *RoleUseCase) GrantModerator(ctx context.Context, forum string, nickname string) (moderator *models.Moderator, err error) {
+	ctx, span := startSpan(ctx, "RoleUseCase.GrantModerator")
+	defer func() { endSpan(span, err) }()
+
+	found, err := usecase.forum(ctx, "RoleUseCase.GrantModerator", forum)
+	if err != nil {
+		return
+	}
+	if err = usecase.Authorize(ctx, permissions.ManageModerators, found.Slug, ""); err != nil {
+		return
+	}
+
+	moderator, err = usecase.roleRepository.GrantModerator(ctx, found.Slug, nickname, auth.User(ctx))
+	if err != nil {
+		pgconErr, ok := err.(*pgconn.PgError)
+		if ok && pgconErr.SQLState() == errors.Err23502 {
+			err = errors.NotFoundUser
+			moderator = nil
+			return
+		}
+		err = internalError(ctx, usecase.logger, "RoleUseCase.GrantModerator", err)
+		moderator = nil
+		return
+	}
+
+	usecase.logger.InfoContext(ctx, "moderator granted", slog.String("forum", found.Slug),
+		slog.String("nickname", moderator.Nickname), slog.String("by", auth.User(ctx)))
+	return
+}
+
+func (usecase *RoleUseCase) RevokeModerator(ctx context.Context, forum string, nickname string) (err error) {
+	ctx, span := startSpan(ctx, "RoleUseCase.RevokeModerator")
+	defer func() { endSpan(span, err) }()
+
+	found, err := usecase.forum(ctx, "RoleUseCase.RevokeModerator", forum)
+	if err != nil {
+		return
+	}
+	if err = usecase.Authorize(ctx, permissions.ManageModerators, found.Slug, ""); err != nil {
+		return
+	}
+
+	revoked, err := usecase.roleRepository.RevokeModerator(ctx, found.Slug, nickname)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "RoleUseCase.RevokeModerator", err)
+		return
+	}
+	if !revoked {
+		err = errors.ModeratorNotFound
+		return
+	}
+
+	usecase.logger.InfoContext(ctx, "moderator revoked", slog.String("forum", found.Slug),
+		slog.String("nickname", nickname), slog.String("by", auth.User(ctx)))
+	return
+}
+
+func (usecase *RoleUseCase) forum(ctx context.Context, op string, slug string) (forum *models.Forum, err error) {
+	forum, err = usecase.forumRepository.Get(ctx, slug)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.NotFoundForum
+			return
+		}
+		err = internalError(ctx, usecase.logger, op, err)
+	}
+	return
+}
+
+// permissionError maps a refusal of the permissions package to the API error.
+func permissionError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, permissions.ErrUnauthenticated):
+		return errors.AuthRequired
+	case errors.Is(err, permissions.ErrForbidden), errors.Is(err, permissions.ErrSessionRequired):
+		return errors.Forbidden
+	case errors.Is(err, permissions.ErrKeyForum):
+		return errors.APIKeyForum
+	default:
+		return errors.ServerInternal.Wrap(err)
+	}
+}
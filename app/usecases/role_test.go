@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// forumRoles gives users their role per forum; admins are admins everywhere.
+type forumRoles struct {
+	repositories.IRoleRepository
+	admins     map[string]bool
+	owners     map[string]string
+	moderators map[string]string
+}
+
+func (repo forumRoles) Role(ctx context.Context, nickname string, forum string) (string, error) {
+	nickname = strings.ToLower(nickname)
+	switch {
+	case repo.admins[nickname]:
+		return "admin", nil
+	case forum != "" && strings.EqualFold(repo.owners[nickname], forum):
+		return "owner", nil
+	case forum != "" && strings.EqualFold(repo.moderators[nickname], forum):
+		return "moderator", nil
+	}
+	return "member", nil
+}
+
+func TestAuthorize(t *testing.T) {
+	roles := forumRoles{
+		admins:     map[string]bool{"root": true},
+		owners:     map[string]string{"olga": "pirates"},
+		moderators: map[string]string{"bob": "pirates"},
+	}
+	usecase := CreateRoleUseCase(roles, nil, false, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	user := func(nickname string) context.Context {
+		return auth.WithUser(context.Background(), nickname)
+	}
+	key := func(nickname string, forum string) context.Context {
+		return auth.WithAPIKey(context.Background(), &auth.APIKey{Nickname: nickname, Forum: forum})
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		action permissions.Action
+		forum  string
+		author string
+		want   error
+	}{
+		{"member deletes own post", user("alice"), permissions.DeletePost, "pirates", "alice", nil},
+		{"member may not delete others' posts", user("alice"), permissions.DeletePost, "pirates", "carol", errors.Forbidden},
+		{"member may not grant moderators", user("alice"), permissions.ManageModerators, "pirates", "", errors.Forbidden},
+		{"member may not clear", user("alice"), permissions.Clear, "", "", errors.Forbidden},
+		{"member may not edit others' profiles", user("alice"), permissions.EditProfile, "", "carol", errors.Forbidden},
+
+		{"moderator purges in own forum", user("bob"), permissions.PurgePost, "pirates", "carol", nil},
+		{"moderator may not purge elsewhere", user("bob"), permissions.PurgePost, "sailors", "carol", errors.Forbidden},
+		{"moderator may not grant moderators", user("bob"), permissions.ManageModerators, "pirates", "", errors.Forbidden},
+		{"moderator may not delete the forum", user("bob"), permissions.DeleteForum, "pirates", "", errors.Forbidden},
+
+		{"owner grants moderators", user("olga"), permissions.ManageModerators, "pirates", "", nil},
+		{"owner may not grant elsewhere", user("olga"), permissions.ManageModerators, "sailors", "", errors.Forbidden},
+		{"owner may not clear", user("olga"), permissions.Clear, "", "", errors.Forbidden},
+
+		{"admin clears", user("root"), permissions.Clear, "", "", nil},
+		{"admin edits any profile", user("root"), permissions.EditProfile, "", "carol", nil},
+		{"admin key may not clear", key("root", ""), permissions.Clear, "", "", errors.Forbidden},
+		{"owner key may not grant moderators", key("olga", ""), permissions.ManageModerators, "pirates", "", errors.Forbidden},
+		{"forum key stays in its forum", key("bob", "pirates"), permissions.DeletePost, "sailors", "bob", errors.APIKeyForum},
+
+		{"anonymous may not delete posts", context.Background(), permissions.DeletePost, "pirates", "alice", errors.AuthRequired},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := usecase.Authorize(test.ctx, test.action, test.forum, test.author); !errors.Is(err, test.want) {
+				t.Errorf("Authorize(%s, %q, %q) error = %v, want %v", test.action, test.forum, test.author, err, test.want)
+			}
+		})
+	}
+}
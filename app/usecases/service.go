@@ -1,38 +1,243 @@
 package usecases
 
 import (
+	"context"
 	"db_project/app/models"
 	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/constants"
 	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
 )
 
+const counterCompactInterval = time.Minute
+
 type IServiceUseCase interface {
-	Clear() (err error)
-	Status() (status *models.ForumStatus, err error)
+	Clear(ctx context.Context, params *models.ClearQueryParams) (report *models.ClearReport, err error)
+	Status(ctx context.Context, params *models.StatusQueryParams) (status *models.ForumStatus, err error)
+	Ready(ctx context.Context) (status *models.HealthStatus, err error)
+	Health(ctx context.Context) (report *models.HealthReport, err error)
+}
+
+// MigrationChecker reports whether the schema is up to date, see migrations.Migrator.
+type MigrationChecker interface {
+	Current(ctx context.Context) (current bool, err error)
 }
 
 type ServiceUseCase struct {
 	serviceRepository repositories.IServiceRepository
+	threadUseCase     IThreadUseCase
+	roleUseCase       IRoleUseCase
+	migrations        MigrationChecker
+	logger            *slog.Logger
+	lastCompact       atomic.Int64
 }
 
-func CreateServiceUseCase(serviceRepository repositories.IServiceRepository) IServiceUseCase {
-	return &ServiceUseCase{serviceRepository: serviceRepository}
+func CreateServiceUseCase(serviceRepository repositories.IServiceRepository, threadUseCase IThreadUseCase,
+	roleUseCase IRoleUseCase, migrations MigrationChecker, logger *slog.Logger) IServiceUseCase {
+	return &ServiceUseCase{serviceRepository: serviceRepository, threadUseCase: threadUseCase,
+		roleUseCase: roleUseCase, migrations: migrations, logger: logger}
 }
 
-func (usecase *ServiceUseCase) Clear() (err error) {
-	err = usecase.serviceRepository.Clear()
-	if err != nil {
-		err = errors.ServerInternal
+// Clear deletes everything, one forum or one thread depending on params.
+// Only admins may clear, and clearing everything keeps the users with a role
+// so that admins aren't locked out. Every clear that is not a dry run is
+// logged with what it deleted and who asked for it.
+func (usecase *ServiceUseCase) Clear(ctx context.Context, params *models.ClearQueryParams) (report *models.ClearReport, err error) {
+	ctx, span := startSpan(ctx, "ServiceUseCase.Clear")
+	defer func() { endSpan(span, err) }()
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.Clear, "", ""); err != nil {
+		return
+	}
+
+	var scope slog.Attr
+	switch {
+	case params.Forum != "" && params.Thread != "":
+		err = errors.BadRequest.WithDetails("forum_or_thread")
 		return
+	case params.Forum != "":
+		scope = slog.String("forum", params.Forum)
+		report, err = usecase.serviceRepository.ClearForum(ctx, params.Forum, params.DryRun)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				err = errors.NotFoundForum
+			} else {
+				err = internalError(ctx, usecase.logger, "ServiceUseCase.Clear", err)
+			}
+			return
+		}
+	case params.Thread != "":
+		var thread *models.Thread
+		// Deleted threads can be cleared for good too.
+		thread, err = usecase.threadUseCase.GetIncludingDeleted(ctx, params.Thread)
+		if err != nil {
+			return
+		}
+		scope = slog.Int("thread", thread.ID)
+		report, err = usecase.serviceRepository.ClearThread(ctx, thread, params.DryRun)
+		if err != nil {
+			if err == pgx.ErrNoRows {
+				err = errors.ThreadNotFound
+			} else {
+				err = internalError(ctx, usecase.logger, "ServiceUseCase.Clear", err)
+			}
+			return
+		}
+	default:
+		scope = slog.String("scope", "all")
+		var status *models.ForumStatus
+		status, err = usecase.serviceRepository.Status(ctx)
+		if err != nil {
+			err = internalError(ctx, usecase.logger, "ServiceUseCase.Clear", err)
+			return
+		}
+		var kept int
+		kept, err = usecase.serviceRepository.KeptUsers(ctx)
+		if err != nil {
+			err = internalError(ctx, usecase.logger, "ServiceUseCase.Clear", err)
+			return
+		}
+		report = &models.ClearReport{DryRun: params.DryRun, Forum: status.Forum, Thread: status.Thread,
+			Post: status.Post, Vote: status.Vote, ForumUser: status.ForumUser, User: max(status.User-kept, 0)}
+		if !params.DryRun {
+			if err = usecase.serviceRepository.Clear(ctx); err != nil {
+				report = nil
+				err = internalError(ctx, usecase.logger, "ServiceUseCase.Clear", err)
+				return
+			}
+		}
+	}
+
+	if !report.DryRun {
+		usecase.logger.InfoContext(ctx, "database cleared", scope, slog.String("by", auth.User(ctx)),
+			slog.Int("forums", report.Forum), slog.Int("threads", report.Thread), slog.Int("posts", report.Post),
+			slog.Int("votes", report.Vote), slog.Int("forum_users", report.ForumUser), slog.Int("users", report.User))
 	}
 	return
 }
 
-func (usecase *ServiceUseCase) Status() (status *models.ForumStatus, err error) {
-	status, err = usecase.serviceRepository.Status()
+func (usecase *ServiceUseCase) Status(ctx context.Context, params *models.StatusQueryParams) (status *models.ForumStatus, err error) {
+	ctx, span := startSpan(ctx, "ServiceUseCase.Status")
+	defer func() { endSpan(span, err) }()
+
+	if params.Exact {
+		status, err = usecase.serviceRepository.ExactStatus(ctx)
+	} else {
+		status, err = usecase.serviceRepository.Status(ctx)
+		usecase.compactCounters()
+	}
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "ServiceUseCase.Status", err)
+		return
+	}
+
+	status.Durability, err = usecase.serviceRepository.Durability(ctx)
 	if err != nil {
-		err = errors.ServerInternal
+		status = nil
+		err = internalError(ctx, usecase.logger, "ServiceUseCase.Status", err)
+		return
+	}
+	return
+}
+
+// compactCounters folds the counters in the background, at most once per
+// counterCompactInterval, so the rows summed by Status don't pile up.
+func (usecase *ServiceUseCase) compactCounters() {
+	last := usecase.lastCompact.Load()
+	now := time.Now()
+	if now.Sub(time.Unix(0, last)) < counterCompactInterval || !usecase.lastCompact.CompareAndSwap(last, now.UnixNano()) {
 		return
 	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), counterCompactInterval)
+		defer cancel()
+		if err := usecase.serviceRepository.CompactCounters(ctx); err != nil {
+			usecase.logger.WarnContext(ctx, "failed to compact counters", slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// Ready checks that a connection can be acquired and that the migrations are
+// current. It only runs cheap queries so that it can be probed often.
+func (usecase *ServiceUseCase) Ready(ctx context.Context) (status *models.HealthStatus, err error) {
+	ctx, span := startSpan(ctx, "ServiceUseCase.Ready")
+	defer func() { endSpan(span, err) }()
+
+	if pingErr := usecase.serviceRepository.Ping(ctx); pingErr != nil {
+		usecase.logger.WarnContext(ctx, "readiness check failed", slog.String("error", pingErr.Error()))
+		status = &models.HealthStatus{Status: "not ready", Reason: "database unavailable"}
+		return
+	}
+
+	current, migrationsErr := usecase.migrations.Current(ctx)
+	if migrationsErr != nil {
+		usecase.logger.WarnContext(ctx, "readiness check failed", slog.String("error", migrationsErr.Error()))
+		status = &models.HealthStatus{Status: "not ready", Reason: "can't check migrations"}
+		return
+	}
+	if !current {
+		status = &models.HealthStatus{Status: "not ready", Reason: "migrations are not current"}
+		return
+	}
+
+	status = &models.HealthStatus{Status: "ready"}
+	return
+}
+
+// Health reports the database latency, the pool saturation, the migrations
+// and the presence of the triggers the counters rely on. Failed checks are
+// part of the report rather than errors.
+func (usecase *ServiceUseCase) Health(ctx context.Context) (report *models.HealthReport, err error) {
+	ctx, span := startSpan(ctx, "ServiceUseCase.Health")
+	defer func() { endSpan(span, err) }()
+
+	report = &models.HealthReport{Status: constants.HealthOK, Triggers: make([]models.TriggerHealth, 0)}
+
+	start := time.Now()
+	pingErr := usecase.serviceRepository.Ping(ctx)
+	report.Database.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+	report.Pool = *usecase.serviceRepository.Pool()
+	if pingErr != nil {
+		report.Status = constants.HealthUnavailable
+		report.Database.Error = pingErr.Error()
+		return
+	}
+	report.Database.Reachable = true
+
+	current, migrationsErr := usecase.migrations.Current(ctx)
+	report.Migrations.Current = current
+	if migrationsErr != nil {
+		report.Migrations.Error = migrationsErr.Error()
+	}
+
+	names, triggersErr := usecase.serviceRepository.Triggers(ctx)
+	if triggersErr != nil {
+		err = internalError(ctx, usecase.logger, "ServiceUseCase.Health", triggersErr)
+		report = nil
+		return
+	}
+	allPresent := true
+	for _, expected := range constants.Triggers {
+		present := false
+		for _, name := range names {
+			// Unquoted trigger names are folded to lower case by PostgreSQL.
+			if strings.EqualFold(name, expected) {
+				present = true
+				break
+			}
+		}
+		allPresent = allPresent && present
+		report.Triggers = append(report.Triggers, models.TriggerHealth{Name: expected, Present: present})
+	}
+
+	if !current || !allPresent || report.Pool.Saturation >= constants.PoolSaturationDegraded {
+		report.Status = constants.HealthDegraded
+	}
 	return
 }
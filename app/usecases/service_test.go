@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/errors"
+	"db_project/utils/permissions"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// clearRepository counts the rows of a database with users, some of them
+// admins, and records whether Clear ran. Other methods aren't used by Clear.
+type clearRepository struct {
+	repositories.IServiceRepository
+	status  models.ForumStatus
+	kept    int
+	cleared bool
+}
+
+func (repo *clearRepository) Status(ctx context.Context) (*models.ForumStatus, error) {
+	status := repo.status
+	return &status, nil
+}
+
+func (repo *clearRepository) KeptUsers(ctx context.Context) (int, error) {
+	return repo.kept, nil
+}
+
+func (repo *clearRepository) Clear(ctx context.Context) error {
+	repo.cleared = true
+	return nil
+}
+
+// allowAll lets everybody do anything.
+type allowAll struct {
+	IRoleUseCase
+}
+
+func (allowAll) Authorize(ctx context.Context, action permissions.Action, forum string, author string) error {
+	return nil
+}
+
+func TestClearKeepsUsersWithRoles(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	status := models.ForumStatus{Forum: 2, Thread: 3, Post: 10, Vote: 4, ForumUser: 5, User: 7}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{"clear", false},
+		{"dry run", true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &clearRepository{status: status, kept: 2}
+			usecase := CreateServiceUseCase(repo, nil, allowAll{}, nil, logger)
+
+			report, err := usecase.Clear(context.Background(), &models.ClearQueryParams{DryRun: test.dryRun})
+			if err != nil {
+				t.Fatalf("Clear() error = %v", err)
+			}
+			want := models.ClearReport{DryRun: test.dryRun, Forum: 2, Thread: 3, Post: 10, Vote: 4, ForumUser: 5, User: 5}
+			if *report != want {
+				t.Errorf("Clear() = %+v, want %+v without the 2 users kept", *report, want)
+			}
+			if repo.cleared == test.dryRun {
+				t.Errorf("repository cleared = %v with dry run %v", repo.cleared, test.dryRun)
+			}
+		})
+	}
+}
+
+// deletedThread finds thread 42 only among the deleted threads.
+type deletedThread struct {
+	IThreadUseCase
+}
+
+func (deletedThread) Get(ctx context.Context, slugOrId string) (*models.Thread, error) {
+	return nil, errors.ThreadNotFound
+}
+
+func (deletedThread) GetIncludingDeleted(ctx context.Context, slugOrId string) (*models.Thread, error) {
+	return &models.Thread{ID: 42, Forum: "pirates"}, nil
+}
+
+func (repo *clearRepository) ClearThread(ctx context.Context, thread *models.Thread, dryRun bool) (*models.ClearReport, error) {
+	repo.cleared = thread.ID == 42
+	return &models.ClearReport{DryRun: dryRun, Thread: 1}, nil
+}
+
+func TestClearDeletedThread(t *testing.T) {
+	repo := &clearRepository{}
+	usecase := CreateServiceUseCase(repo, deletedThread{}, allowAll{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if _, err := usecase.Clear(context.Background(), &models.ClearQueryParams{Thread: "42"}); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if !repo.cleared {
+		t.Error("Clear() didn't clear the deleted thread")
+	}
+}
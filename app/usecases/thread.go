@@ -1,100 +1,149 @@
 package usecases
 
 import (
+	"context"
 	"db_project/app/models"
 	"db_project/app/repositories"
+	"db_project/utils/auth"
 	"db_project/utils/errors"
-	"db_project/utils/queryCheck"
+	"db_project/utils/permissions"
+	"db_project/utils/validation"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"log/slog"
+	"strconv"
 )
 
 type IThreadUseCase interface {
-	Get(slugOrId string) (thread *models.Thread, err error)
-	Update(slugOrId string, thread *models.Thread) (updatedThread *models.Thread, err error)
-	Vote(slugOrId string, vote *models.Vote) (thread *models.Thread, err error)
-	CreatePosts(slugOrId string, posts []*models.Post) (createdPosts []*models.Post, err error)
-	GetPosts(slugOrId string, params *models.PostsQueryParams) (posts []*models.Post, err error)
+	Get(ctx context.Context, slugOrId string) (thread *models.Thread, err error)
+	// GetIncludingDeleted is Get falling back to deleted threads, for purging them.
+	GetIncludingDeleted(ctx context.Context, slugOrId string) (thread *models.Thread, err error)
+	Update(ctx context.Context, slugOrId string, thread *models.Thread) (updatedThread *models.Thread, err error)
+	Vote(ctx context.Context, slugOrId string, vote *models.Vote) (thread *models.Thread, err error)
+	CreatePosts(ctx context.Context, slugOrId string, posts []*models.Post) (createdPosts []*models.Post, err error)
+	GetPosts(ctx context.Context, slugOrId string, params *models.PostsQueryParams) (posts []*models.Post, err error)
+	Delete(ctx context.Context, slugOrId string, hard bool) (err error)
+	Restore(ctx context.Context, slugOrId string) (thread *models.Thread, err error)
+	Archive(ctx context.Context, slugOrId string, archived bool) (thread *models.Thread, err error)
 }
 
 type ThreadUseCase struct {
-	threadRepository repositories.IThreadRepository
+	threadRepository  repositories.IThreadRepository
+	serviceRepository repositories.IServiceRepository
+	roleUseCase       IRoleUseCase
+	logger            *slog.Logger
 }
 
-func CreateThreadUseCase(threadRepository repositories.IThreadRepository) IThreadUseCase {
-	return &ThreadUseCase{threadRepository: threadRepository}
+func CreateThreadUseCase(threadRepository repositories.IThreadRepository,
+	serviceRepository repositories.IServiceRepository,
+	roleUseCase IRoleUseCase,
+	logger *slog.Logger) IThreadUseCase {
+	return &ThreadUseCase{threadRepository: threadRepository, serviceRepository: serviceRepository, roleUseCase: roleUseCase, logger: logger}
 }
 
-func (usecase *ThreadUseCase) Get(slugOrId string) (thread *models.Thread, err error) {
-	v, _ := queryCheck.GetInstance()
-	slug, id, err := v.GetSlugOrIdOrErr(slugOrId)
+func (usecase *ThreadUseCase) Get(ctx context.Context, slugOrId string) (thread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Get")
+	defer func() { endSpan(span, err) }()
+
+	slug, id, err := validation.SlugOrID("slug_or_id", slugOrId)
 	if err != nil {
-		err = errors.BadRequest.SetTextDetails(err.Error())
 		return
 	}
 
 	if slug == "" {
-		thread, err = usecase.threadRepository.GetByID(id)
+		thread, err = usecase.threadRepository.GetByID(ctx, id)
 	} else {
-		thread, err = usecase.threadRepository.GetBySlug(slug)
+		thread, err = usecase.threadRepository.GetBySlug(ctx, slug)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			err = errors.ThreadNotFound
 		} else {
-			err = errors.ServerInternal
+			err = internalError(ctx, usecase.logger, "ThreadUseCase.Get", err)
 		}
 	}
 
 	return
 }
 
-func (usecase *ThreadUseCase) Update(slugOrId string, thread *models.Thread) (updatedThread *models.Thread, err error) {
-	v, _ := queryCheck.GetInstance()
-	slug, id, err := v.GetSlugOrIdOrErr(slugOrId)
+func (usecase *ThreadUseCase) Update(ctx context.Context, slugOrId string, thread *models.Thread) (updatedThread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Update")
+	defer func() { endSpan(span, err) }()
+
+	slug, id, err := validation.SlugOrID("slug_or_id", slugOrId)
 	if err != nil {
-		err = errors.BadRequest.SetTextDetails(err.Error())
+		return
+	}
+
+	current, err := usecase.Get(ctx, slugOrId)
+	if err != nil {
+		if errors.Is(err, errors.ThreadNotFound) {
+			err = errors.ThreadUpdateNotFound
+		}
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.EditThread, current.Forum, current.Author); err != nil {
+		return
+	}
+	if current.IsArchived {
+		err = errors.ThreadArchived
 		return
 	}
 
 	if slug == "" {
 		thread.ID = id
-		updatedThread, err = usecase.threadRepository.UpdateByID(thread)
+		updatedThread, err = usecase.threadRepository.UpdateByID(ctx, thread)
 	} else {
 		thread.Slug = slug
-		updatedThread, err = usecase.threadRepository.UpdateBySlug(thread)
+		updatedThread, err = usecase.threadRepository.UpdateBySlug(ctx, thread)
 	}
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			err = errors.ThreadUpdateNotFound
+			if current, getErr := usecase.Get(ctx, slugOrId); getErr == nil && current.IsArchived {
+				err = errors.ThreadArchived
+			}
 			return
 		}
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Update", err)
 		return
 	}
 
 	return
 }
 
-func (usecase *ThreadUseCase) Vote(slugOrId string, vote *models.Vote) (thread *models.Thread, err error) {
-	v, _ := queryCheck.GetInstance()
-	if !v.CheckVote(vote) {
-		err = errors.BadRequest.SetTextDetails("не верное значение голоса")
+func (usecase *ThreadUseCase) Vote(ctx context.Context, slugOrId string, vote *models.Vote) (thread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Vote")
+	defer func() { endSpan(span, err) }()
+
+	slug, id, err := validation.SlugOrID("slug_or_id", slugOrId)
+	if err != nil {
 		return
 	}
 
-	slug, id, err := v.GetSlugOrIdOrErr(slugOrId)
-	if err != nil {
-		err = errors.BadRequest.SetTextDetails(err.Error())
+	// Keys restricted to a forum need the forum of the thread first.
+	var forum string
+	if key := auth.Key(ctx); key != nil && key.Forum != "" {
+		if thread, err = usecase.Get(ctx, slugOrId); err != nil {
+			return
+		}
+		if thread.IsArchived {
+			err = errors.ThreadArchived
+			return
+		}
+		forum = thread.Forum
+	}
+	if err = checkActor(ctx, vote.Username, forum); err != nil {
 		return
 	}
 
 	if slug == "" {
-		err = usecase.threadRepository.VoteByID(id, vote)
+		err = usecase.threadRepository.VoteByID(ctx, id, vote)
 	} else {
-		err = usecase.threadRepository.VoteBySlug(slug, vote)
+		err = usecase.threadRepository.VoteBySlug(ctx, slug, vote)
 	}
 
 	if err != nil {
@@ -104,38 +153,62 @@ func (usecase *ThreadUseCase) Vote(slugOrId string, vote *models.Vote) (thread *
 				err = errors.ThreadUserOrThreadNotFound
 				return
 			} else {
-				err = errors.ServerInternal.SetTextDetails(err.Error())
+				err = internalError(ctx, usecase.logger, "ThreadUseCase.Vote", err)
 				return
 			}
 		}
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Vote", err)
+		return
 	}
 
+	// Votes for threads that are deleted or archived are left out.
 	if slug == "" {
-		thread, err = usecase.threadRepository.GetByID(id)
+		thread, err = usecase.threadRepository.GetByID(ctx, id)
 	} else {
-		thread, err = usecase.threadRepository.GetBySlug(slug)
+		thread, err = usecase.threadRepository.GetBySlug(ctx, slug)
 	}
 
 	if err != nil {
-		err = errors.ServerInternal
+		if err == pgx.ErrNoRows {
+			err = errors.ThreadUserOrThreadNotFound
+			return
+		}
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Vote", err)
 		return
 	}
+	if thread.IsArchived {
+		thread = nil
+		err = errors.ThreadArchived
+	}
 
 	return
 }
 
-func (usecase *ThreadUseCase) CreatePosts(slugOrId string, posts []*models.Post) (createdPosts []*models.Post, err error) {
-	thread, err := usecase.Get(slugOrId)
+func (usecase *ThreadUseCase) CreatePosts(ctx context.Context, slugOrId string, posts []*models.Post) (createdPosts []*models.Post, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.CreatePosts")
+	defer func() { endSpan(span, err) }()
+
+	thread, err := usecase.Get(ctx, slugOrId)
 	if err != nil {
 		return
 	}
+	if thread.IsArchived {
+		err = errors.ThreadArchived
+		return
+	}
+
+	for _, post := range posts {
+		if err = checkActor(ctx, post.Author, thread.Forum); err != nil {
+			return
+		}
+	}
 
 	if len(posts) == 0 {
 		createdPosts = make([]*models.Post, 0)
 		return
 	}
 
-	createdPosts, err = usecase.threadRepository.CreatePosts(thread.ID, thread.Forum, posts)
+	createdPosts, err = usecase.threadRepository.CreatePosts(ctx, thread.ID, thread.Forum, posts)
 	if err != nil {
 		pgconErr, ok := err.(*pgconn.PgError)
 		if ok {
@@ -149,24 +222,145 @@ func (usecase *ThreadUseCase) CreatePosts(slugOrId string, posts []*models.Post)
 				return
 
 			default:
-				err = errors.ServerInternal
+				err = internalError(ctx, usecase.logger, "ThreadUseCase.CreatePosts", err)
 			}
+		} else {
+			err = internalError(ctx, usecase.logger, "ThreadUseCase.CreatePosts", err)
 		}
 	}
 
 	return
 }
 
-func (usecase *ThreadUseCase) GetPosts(slugOrId string, params *models.PostsQueryParams) (posts []*models.Post, err error) {
-	thread, err := usecase.Get(slugOrId)
+func (usecase *ThreadUseCase) GetPosts(ctx context.Context, slugOrId string, params *models.PostsQueryParams) (posts []*models.Post, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.GetPosts")
+	defer func() { endSpan(span, err) }()
+
+	thread, err := usecase.Get(ctx, slugOrId)
 	if err != nil {
 		return
 	}
 
-	posts, err = usecase.threadRepository.GetPosts(thread.ID, params)
+	posts, err = usecase.threadRepository.GetPosts(ctx, thread.ID, params)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.GetPosts", err)
+	}
+
+	return
+}
+
+// Delete hides a thread with its posts, or removes it for good with its posts
+// and votes when hard is set. Deleted threads can be removed for good too.
+func (usecase *ThreadUseCase) Delete(ctx context.Context, slugOrId string, hard bool) (err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Delete", attribute.Bool("thread.hard", hard))
+	defer func() { endSpan(span, err) }()
+
+	var thread *models.Thread
+	if hard {
+		thread, err = usecase.GetIncludingDeleted(ctx, slugOrId)
+	} else {
+		thread, err = usecase.Get(ctx, slugOrId)
+	}
 	if err != nil {
-		err = errors.ServerInternal
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.DeleteThread, thread.Forum, ""); err != nil {
+		return
 	}
 
+	if hard {
+		_, err = usecase.serviceRepository.ClearThread(ctx, thread, false)
+	} else {
+		err = usecase.threadRepository.Delete(ctx, thread.ID)
+	}
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.ThreadNotFound
+			return
+		}
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Delete", err)
+	}
+	return
+}
+
+// Restore brings a deleted thread back. Restoring a thread that isn't
+// deleted returns it unchanged.
+func (usecase *ThreadUseCase) Restore(ctx context.Context, slugOrId string) (thread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Restore")
+	defer func() { endSpan(span, err) }()
+
+	deleted, err := usecase.getDeleted(ctx, slugOrId)
+	if errors.Is(err, errors.ThreadNotFound) {
+		if thread, err = usecase.Get(ctx, slugOrId); err != nil {
+			return
+		}
+		err = usecase.roleUseCase.Authorize(ctx, permissions.DeleteThread, thread.Forum, "")
+		if err != nil {
+			thread = nil
+		}
+		return
+	}
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.DeleteThread, deleted.Forum, ""); err != nil {
+		return
+	}
+
+	if err = usecase.threadRepository.Restore(ctx, deleted.ID); err != nil {
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Restore", err)
+		return
+	}
+
+	return usecase.Get(ctx, strconv.Itoa(deleted.ID))
+}
+
+// Archive makes a thread read-only and leaves it out of the forum listings,
+// or undoes that when archived is false.
+func (usecase *ThreadUseCase) Archive(ctx context.Context, slugOrId string, archived bool) (thread *models.Thread, err error) {
+	ctx, span := startSpan(ctx, "ThreadUseCase.Archive", attribute.Bool("thread.archived", archived))
+	defer func() { endSpan(span, err) }()
+
+	current, err := usecase.Get(ctx, slugOrId)
+	if err != nil {
+		return
+	}
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.ArchiveThread, current.Forum, ""); err != nil {
+		return
+	}
+
+	thread, err = usecase.threadRepository.Archive(ctx, current.ID, archived)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.ThreadNotFound
+			return
+		}
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.Archive", err)
+	}
+	return
+}
+
+func (usecase *ThreadUseCase) GetIncludingDeleted(ctx context.Context, slugOrId string) (thread *models.Thread, err error) {
+	thread, err = usecase.Get(ctx, slugOrId)
+	if errors.Is(err, errors.ThreadNotFound) {
+		thread, err = usecase.getDeleted(ctx, slugOrId)
+	}
+	return
+}
+
+func (usecase *ThreadUseCase) getDeleted(ctx context.Context, slugOrId string) (thread *models.Thread, err error) {
+	slug, id, err := validation.SlugOrID("slug_or_id", slugOrId)
+	if err != nil {
+		return
+	}
+
+	thread, err = usecase.threadRepository.GetDeleted(ctx, slug, id)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			err = errors.ThreadNotFound
+			return
+		}
+		err = internalError(ctx, usecase.logger, "ThreadUseCase.getDeleted", err)
+	}
 	return
 }
@@ -0,0 +1,77 @@
+package usecases
+
+import (
+	"context"
+	"db_project/app/models"
+	"db_project/app/repositories"
+	"db_project/utils/auth"
+	"db_project/utils/errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// memberRoles makes every user a plain member.
+type memberRoles struct {
+	repositories.IRoleRepository
+}
+
+func (memberRoles) Role(ctx context.Context, nickname string, forum string) (string, error) {
+	return "member", nil
+}
+
+// editThreads holds a single thread by alice and records updates.
+type editThreads struct {
+	repositories.IThreadRepository
+	updated bool
+}
+
+func (repo *editThreads) GetByID(ctx context.Context, id int) (*models.Thread, error) {
+	return &models.Thread{ID: id, Author: "alice", Forum: "pirates", Title: "Treasure"}, nil
+}
+
+func (repo *editThreads) UpdateByID(ctx context.Context, thread *models.Thread) (*models.Thread, error) {
+	repo.updated = true
+	return thread, nil
+}
+
+// editTests are the actors editing alice's thread or post and the outcome.
+var editTests = []struct {
+	name           string
+	user           string
+	anonymousEdits bool
+	want           error
+}{
+	{"anonymous refused", "", false, errors.AuthRequired},
+	{"anonymous with anonymous_edits", "", true, nil},
+	{"author", "alice", false, nil},
+	{"other member", "carol", false, errors.Forbidden},
+	{"other member with anonymous_edits", "carol", true, errors.Forbidden},
+}
+
+func editContext(user string) context.Context {
+	if user == "" {
+		return context.Background()
+	}
+	return auth.WithUser(context.Background(), user)
+}
+
+func TestThreadUpdateAuthorization(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	for _, test := range editTests {
+		t.Run(test.name, func(t *testing.T) {
+			repo := &editThreads{}
+			roles := CreateRoleUseCase(memberRoles{}, nil, test.anonymousEdits, logger)
+			usecase := CreateThreadUseCase(repo, nil, roles, logger)
+
+			_, err := usecase.Update(editContext(test.user), "42", &models.Thread{Msg: "Found it"})
+			if !errors.Is(err, test.want) {
+				t.Errorf("Update() error = %v, want %v", err, test.want)
+			}
+			if repo.updated != (test.want == nil) {
+				t.Errorf("thread updated = %v, want %v", repo.updated, test.want == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+	"db_project/utils/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+)
+
+var tracer = otel.Tracer("db_project/app/usecases")
+
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on the span and ends it. Only server side errors mark
+// the span as failed, client errors such as "not found" are expected outcomes.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		var apiErr *errors.Error
+		if !errors.As(err, &apiErr) || apiErr.Code() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, err.Error())
+		}
+	}
+	span.End()
+}
@@ -1,67 +1,121 @@
 package usecases
 
 import (
+	"context"
 	"db_project/app/models"
 	"db_project/app/repositories"
+	"db_project/utils/auth"
 	"db_project/utils/errors"
+	"db_project/utils/permissions"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
+	"log/slog"
+	"strings"
 )
 
 type IUserUseCase interface {
-	Get(nickname *string) (user *models.User, err error)
-	All() (users *[]models.User, err error)
-	Create(user *models.User) (users []*models.User, err error)
-	Update(user *models.User) (updatedUser *models.User, err error)
+	Get(ctx context.Context, nickname *string) (user *models.User, err error)
+	All(ctx context.Context, params *models.UserListQueryParams) (users []*models.User, err error)
+	Create(ctx context.Context, user *models.User) (users []*models.User, err error)
+	Update(ctx context.Context, user *models.User) (updatedUser *models.User, err error)
 }
 
 type UserUseCase struct {
 	userRepository repositories.IUserRepository
+	authRepository repositories.IAuthRepository
+	roleUseCase    IRoleUseCase
+	logger         *slog.Logger
 }
 
-func CreateUserUseCase(userRepository repositories.IUserRepository) IUserUseCase {
-	return &UserUseCase{userRepository: userRepository}
+func CreateUserUseCase(userRepository repositories.IUserRepository,
+	authRepository repositories.IAuthRepository,
+	roleUseCase IRoleUseCase,
+	logger *slog.Logger) IUserUseCase {
+	return &UserUseCase{userRepository: userRepository, authRepository: authRepository, roleUseCase: roleUseCase, logger: logger}
 }
 
-func (usecase *UserUseCase) Get(nickname *string) (user *models.User, err error) {
-	user, err = usecase.userRepository.Get(nickname)
+func (usecase *UserUseCase) Get(ctx context.Context, nickname *string) (user *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserUseCase.Get")
+	defer func() { endSpan(span, err) }()
+
+	user, err = usecase.userRepository.Get(ctx, nickname)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			err = errors.NotFoundUser
 		} else {
-			err = errors.ServerInternal
+			err = internalError(ctx, usecase.logger, "UserUseCase.Get", err)
 		}
 	}
 	return
 }
 
-func (usecase *UserUseCase) All() (users *[]models.User, err error) {
+// All lists the users by nickname, like the users of a forum, for the user
+// directory and mention autocompletion.
+func (usecase *UserUseCase) All(ctx context.Context, params *models.UserListQueryParams) (users []*models.User, err error) {
+	ctx, span := startSpan(ctx, "UserUseCase.All")
+	defer func() { endSpan(span, err) }()
+
+	users, err = usecase.userRepository.All(ctx, params)
+	if err != nil {
+		err = internalError(ctx, usecase.logger, "UserUseCase.All", err)
+	}
 	return
 }
 
-func (usecase *UserUseCase) Create(user *models.User) (users []*models.User, err error) {
-	err = usecase.userRepository.Create(user)
+func (usecase *UserUseCase) Create(ctx context.Context, user *models.User) (users []*models.User, err error) {
+	ctx, span := startSpan(ctx, "UserUseCase.Create")
+	defer func() { endSpan(span, err) }()
+
+	var passwordHash string
+	if user.Password != "" {
+		if passwordHash, err = auth.HashPassword(user.Password); err != nil {
+			err = internalError(ctx, usecase.logger, "UserUseCase.Create", err)
+			return
+		}
+		user.Password = ""
+	}
+	user.CurrentPassword = ""
+
+	err = usecase.userRepository.Create(ctx, user, passwordHash)
 
 	if err != nil {
 		pgconErr, ok := err.(*pgconn.PgError)
 		if ok && pgconErr.SQLState() == errors.Err23505 {
-			users, err = usecase.userRepository.GetUsersByUserNicknameOrEmail(user)
+			users, err = usecase.userRepository.GetUsersByUserNicknameOrEmail(ctx, user)
 			if err != nil {
-				err = errors.ServerInternal
+				err = internalError(ctx, usecase.logger, "UserUseCase.Create", err)
 				return
 			}
 			err = errors.ConflictUserCreate
 			return
 		}
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "UserUseCase.Create", err)
 		return
 	}
 
 	return
 }
 
-func (usecase *UserUseCase) Update(user *models.User) (updatedUser *models.User, err error) {
-	updatedUser, err = usecase.userRepository.Update(user)
+func (usecase *UserUseCase) Update(ctx context.Context, user *models.User) (updatedUser *models.User, err error) {
+	ctx, span := startSpan(ctx, "UserUseCase.Update")
+	defer func() { endSpan(span, err) }()
+
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.EditProfile, "", user.Username); err != nil {
+		return
+	}
+
+	var passwordHash string
+	if user.Password != "" {
+		if err = usecase.checkPasswordChange(ctx, user); err != nil {
+			return
+		}
+		if passwordHash, err = auth.HashPassword(user.Password); err != nil {
+			err = internalError(ctx, usecase.logger, "UserUseCase.Update", err)
+			return
+		}
+	}
+
+	updatedUser, err = usecase.userRepository.Update(ctx, user, passwordHash)
 
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -73,9 +127,30 @@ func (usecase *UserUseCase) Update(user *models.User) (updatedUser *models.User,
 			err = errors.ConflictUserUpdate
 			return
 		}
-		err = errors.ServerInternal
+		err = internalError(ctx, usecase.logger, "UserUseCase.Update", err)
 		return
 	}
 
 	return
 }
+
+// checkPasswordChange lets users change their own password when they give
+// the current one, and admins reset anyone's. It is never allowed anonymously
+// or with an API key, whatever auth.required is.
+func (usecase *UserUseCase) checkPasswordChange(ctx context.Context, user *models.User) (err error) {
+	if err = usecase.roleUseCase.Authorize(ctx, permissions.ChangePassword, "", user.Username); err != nil {
+		return
+	}
+	if !strings.EqualFold(auth.User(ctx), user.Username) {
+		return
+	}
+
+	_, hash, err := usecase.authRepository.PasswordHash(ctx, user.Username)
+	if err != nil && err != pgx.ErrNoRows {
+		return internalError(ctx, usecase.logger, "UserUseCase.Update", err)
+	}
+	if !auth.CheckPassword(hash, user.CurrentPassword) {
+		return errors.WrongPassword
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"db_project/app/repositories"
+	"db_project/db/migrations"
+	"db_project/utils/auth"
+	"db_project/utils/constants"
+	apperrors "db_project/utils/errors"
+	"errors"
+	"fmt"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const commandsUsage = `commands:
+  migrate up          apply all pending migrations
+  migrate down [N]    revert the last N applied migrations (default 1)
+  migrate status      list migrations and whether they are applied
+  durability          print whether the tables are logged or unlogged
+  durability MODE     rewrite the tables as logged or unlogged
+  admin list          list the users with the admin role
+  admin grant NICK    give NICK the admin role
+  admin revoke NICK   take the admin role from NICK
+  password NICK       set the password of NICK, read from stdin`
+
+// runCommand executes a maintenance command given after the flags instead
+// of starting the server.
+func runCommand(ctx context.Context, db *pgxpool.Pool, logger *slog.Logger, args []string) error {
+	switch {
+	case args[0] == "migrate" && len(args) > 1:
+		return runMigrate(ctx, db, args[1:])
+	case args[0] == "durability":
+		return runDurability(ctx, db, logger, args[1:])
+	case args[0] == "admin" && len(args) > 1:
+		return runAdmin(ctx, db, logger, args[1:])
+	case args[0] == "password" && len(args) == 2:
+		return runPassword(ctx, db, logger, args[1], os.Stdin)
+	default:
+		return fmt.Errorf("unknown command %q\n%s", args, commandsUsage)
+	}
+}
+
+func runMigrate(ctx context.Context, db *pgxpool.Pool, args []string) error {
+	migrator, err := migrations.CreateMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Println("database is up to date")
+		}
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("migrate down expects a positive number of steps, got %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		states, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, state := range states {
+			status := "pending"
+			switch {
+			case state.Unknown:
+				status = "unknown to this binary"
+			case state.Modified:
+				status = "modified after apply"
+			case state.Applied:
+				status = "applied " + state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-30s %s\n", state.Version, state.Name, status)
+		}
+
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", args[0], commandsUsage)
+	}
+
+	return nil
+}
+
+func runDurability(ctx context.Context, db *pgxpool.Pool, logger *slog.Logger, args []string) error {
+	repository := repositories.CreateServiceRepository(repositories.CreateDB(db), logger)
+
+	if len(args) > 0 {
+		if args[0] != constants.DurabilityLogged && args[0] != constants.DurabilityUnlogged {
+			return fmt.Errorf("durability mode must be %s or %s, got %q",
+				constants.DurabilityLogged, constants.DurabilityUnlogged, args[0])
+		}
+		if err := repository.SetDurability(ctx, args[0]); err != nil {
+			return err
+		}
+	}
+
+	mode, err := repository.Durability(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("tables are %s\n", mode)
+	return nil
+}
+
+func runAdmin(ctx context.Context, db *pgxpool.Pool, logger *slog.Logger, args []string) error {
+	repository := repositories.CreateRoleRepository(repositories.CreateDB(db), logger)
+
+	switch {
+	case args[0] == "list":
+		nicknames, err := repository.Admins(ctx)
+		if err != nil {
+			return err
+		}
+		for _, nickname := range nicknames {
+			fmt.Println(nickname)
+		}
+
+	case args[0] == "grant" && len(args) == 2:
+		err := repository.GrantAdmin(ctx, args[1])
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.SQLState() == apperrors.Err23502 {
+			return fmt.Errorf("user %q not found", args[1])
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s is an admin\n", args[1])
+
+	case args[0] == "revoke" && len(args) == 2:
+		revoked, err := repository.RevokeAdmin(ctx, args[1])
+		if err != nil {
+			return err
+		}
+		if !revoked {
+			return fmt.Errorf("%q is not an admin", args[1])
+		}
+		fmt.Printf("%s is no longer an admin\n", args[1])
+
+	default:
+		return fmt.Errorf("unknown admin command %q\n%s", args, commandsUsage)
+	}
+
+	return nil
+}
+
+// runPassword sets the password of a user from the first line of in. It is
+// how users created before passwords existed get one, and how admins recover
+// accounts whose password is lost.
+func runPassword(ctx context.Context, db *pgxpool.Pool, logger *slog.Logger, nickname string, in io.Reader) error {
+	password, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+	password = strings.TrimRight(password, "\r\n")
+	if len(password) < 8 || len(password) > 72 {
+		return fmt.Errorf("password must be 8 to 72 bytes long, got %d", len(password))
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	repository := repositories.CreateAuthRepository(repositories.CreateDB(db), logger)
+	storedNickname, err := repository.SetPassword(ctx, nickname, hash)
+	if err == pgx.ErrNoRows {
+		return fmt.Errorf("user %q not found", nickname)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("password of %s is set\n", storedNickname)
+	return nil
+}
@@ -0,0 +1,6 @@
+-- pgbench transaction: a batch of posts like POST /api/thread/:slug_or_id/create sends.
+\set batch 20
+INSERT INTO posts (parent, author, forum, thread, message, created)
+SELECT NULL, 'bench', 'bench', t.id, 'benchmark post', now()
+FROM threads AS t, generate_series(1, :batch)
+WHERE t.slug = 'bench';
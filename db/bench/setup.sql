@@ -0,0 +1,12 @@
+-- Creates the user, forum and thread the durability benchmark posts into.
+INSERT INTO users (nickname, fullname, about, email)
+VALUES ('bench', 'Bench', '', 'bench@example.com')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO forums (slug, title, "user")
+VALUES ('bench', 'Bench', 'bench')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO threads (slug, author, forum, title, message)
+VALUES ('bench', 'bench', 'bench', 'Bench', 'durability benchmark')
+ON CONFLICT DO NOTHING;
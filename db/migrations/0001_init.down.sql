@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS posts, votes, threads, forum_users, forums, users CASCADE;
+
+DROP FUNCTION IF EXISTS makeVote();
+DROP FUNCTION IF EXISTS updateVote();
+DROP FUNCTION IF EXISTS newPath();
+DROP FUNCTION IF EXISTS threadsCounter();
+DROP FUNCTION IF EXISTS postsCounter();
+DROP FUNCTION IF EXISTS addUser();
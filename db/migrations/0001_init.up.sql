@@ -1,11 +1,3 @@
-DROP SCHEMA IF EXISTS public CASCADE;
-CREATE SCHEMA public;
-
-SET client_encoding = 'UTF8';
-SET client_min_messages = warning;
-SET lock_timeout = 0;
-SET statement_timeout = 0;
-
 CREATE EXTENSION IF NOT EXISTS CITEXT;
 
 CREATE UNLOGGED TABLE users
@@ -191,5 +183,3 @@ CREATE INDEX IF NOT EXISTS sortUsers ON users (nickname, email);
 CREATE INDEX IF NOT EXISTS sortThreadsAndId ON posts (thread, id);
 CREATE INDEX IF NOT EXISTS sortThreadsAndPath ON posts (thread, path);
 CREATE INDEX IF NOT EXISTS sortThreadsAndParent ON posts (thread, (path[1]));
-
-VACUUM ANALYZE;
@@ -0,0 +1,7 @@
+-- Referencing tables have to become unlogged before the tables they reference.
+ALTER TABLE posts SET UNLOGGED;
+ALTER TABLE votes SET UNLOGGED;
+ALTER TABLE threads SET UNLOGGED;
+ALTER TABLE forum_users SET UNLOGGED;
+ALTER TABLE forums SET UNLOGGED;
+ALTER TABLE users SET UNLOGGED;
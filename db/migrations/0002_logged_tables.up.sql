@@ -0,0 +1,7 @@
+-- Referenced tables have to become logged before the tables referencing them.
+ALTER TABLE users SET LOGGED;
+ALTER TABLE forums SET LOGGED;
+ALTER TABLE forum_users SET LOGGED;
+ALTER TABLE threads SET LOGGED;
+ALTER TABLE votes SET LOGGED;
+ALTER TABLE posts SET LOGGED;
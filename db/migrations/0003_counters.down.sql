@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS usersInserted ON users;
+DROP TRIGGER IF EXISTS usersDeleted ON users;
+DROP TRIGGER IF EXISTS usersTruncated ON users;
+DROP TRIGGER IF EXISTS forumsInserted ON forums;
+DROP TRIGGER IF EXISTS forumsDeleted ON forums;
+DROP TRIGGER IF EXISTS forumsTruncated ON forums;
+DROP TRIGGER IF EXISTS forumUsersInserted ON forum_users;
+DROP TRIGGER IF EXISTS forumUsersDeleted ON forum_users;
+DROP TRIGGER IF EXISTS forumUsersTruncated ON forum_users;
+DROP TRIGGER IF EXISTS threadsInserted ON threads;
+DROP TRIGGER IF EXISTS threadsDeleted ON threads;
+DROP TRIGGER IF EXISTS threadsTruncated ON threads;
+DROP TRIGGER IF EXISTS votesInserted ON votes;
+DROP TRIGGER IF EXISTS votesDeleted ON votes;
+DROP TRIGGER IF EXISTS votesTruncated ON votes;
+DROP TRIGGER IF EXISTS postsInserted ON posts;
+DROP TRIGGER IF EXISTS postsDeleted ON posts;
+DROP TRIGGER IF EXISTS postsTruncated ON posts;
+
+DROP FUNCTION IF EXISTS countInserted();
+DROP FUNCTION IF EXISTS countDeleted();
+DROP FUNCTION IF EXISTS countTruncated();
+
+DROP TABLE IF EXISTS counters;
@@ -0,0 +1,107 @@
+-- Row counts reported by GET /api/service/status. They are kept current by
+-- statement-level triggers, so a batch of posts changes a counter once. The
+-- triggers append every change as a new row rather than updating one row per
+-- table, so concurrent writers never wait for each other: the status sums the
+-- rows of every table and folds them back into one row from time to time.
+CREATE TABLE counters
+(
+    name  TEXT   NOT NULL,
+    value BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS countersName ON counters (name);
+
+-- Follow the durability of the other tables, see docs/durability.md.
+DO
+$$
+BEGIN
+    IF (SELECT relpersistence FROM pg_class WHERE oid = 'posts'::regclass) = 'u' THEN
+        ALTER TABLE counters SET UNLOGGED;
+    END IF;
+END;
+$$;
+
+INSERT INTO counters (name, value)
+SELECT 'users', COUNT(*) FROM users
+UNION ALL
+SELECT 'forums', COUNT(*) FROM forums
+UNION ALL
+SELECT 'forum_users', COUNT(*) FROM forum_users
+UNION ALL
+SELECT 'threads', COUNT(*) FROM threads
+UNION ALL
+SELECT 'votes', COUNT(*) FROM votes
+UNION ALL
+SELECT 'posts', COUNT(*) FROM posts;
+
+CREATE OR REPLACE FUNCTION countInserted() RETURNS TRIGGER LANGUAGE plpgsql AS
+$$
+BEGIN
+    INSERT INTO counters (name, value)
+    SELECT TG_TABLE_NAME, COUNT(*) FROM new_rows;
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE OR REPLACE FUNCTION countDeleted() RETURNS TRIGGER LANGUAGE plpgsql AS
+$$
+BEGIN
+    INSERT INTO counters (name, value)
+    SELECT TG_TABLE_NAME, -COUNT(*) FROM old_rows;
+
+    RETURN NULL;
+END;
+$$;
+
+-- TRUNCATE locks the table exclusively, so no change to its count is in flight.
+CREATE OR REPLACE FUNCTION countTruncated() RETURNS TRIGGER LANGUAGE plpgsql AS
+$$
+BEGIN
+    DELETE FROM counters WHERE name = TG_TABLE_NAME;
+
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER usersInserted AFTER INSERT ON users
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER usersDeleted AFTER DELETE ON users
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER usersTruncated AFTER TRUNCATE ON users
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
+
+CREATE TRIGGER forumsInserted AFTER INSERT ON forums
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER forumsDeleted AFTER DELETE ON forums
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER forumsTruncated AFTER TRUNCATE ON forums
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
+
+CREATE TRIGGER forumUsersInserted AFTER INSERT ON forum_users
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER forumUsersDeleted AFTER DELETE ON forum_users
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER forumUsersTruncated AFTER TRUNCATE ON forum_users
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
+
+CREATE TRIGGER threadsInserted AFTER INSERT ON threads
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER threadsDeleted AFTER DELETE ON threads
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER threadsTruncated AFTER TRUNCATE ON threads
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
+
+CREATE TRIGGER votesInserted AFTER INSERT ON votes
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER votesDeleted AFTER DELETE ON votes
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER votesTruncated AFTER TRUNCATE ON votes
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
+
+CREATE TRIGGER postsInserted AFTER INSERT ON posts
+    REFERENCING NEW TABLE AS new_rows FOR EACH STATEMENT EXECUTE PROCEDURE countInserted();
+CREATE TRIGGER postsDeleted AFTER DELETE ON posts
+    REFERENCING OLD TABLE AS old_rows FOR EACH STATEMENT EXECUTE PROCEDURE countDeleted();
+CREATE TRIGGER postsTruncated AFTER TRUNCATE ON posts
+    FOR EACH STATEMENT EXECUTE PROCEDURE countTruncated();
@@ -0,0 +1,27 @@
+-- Users created before passwords existed keep a NULL hash and can't log in
+-- until an admin sets their password, through their profile or with the
+-- password command.
+ALTER TABLE users ADD COLUMN password_hash TEXT;
+
+-- Only a SHA-256 of each refresh token is stored. A token is revoked when it
+-- is exchanged for a new pair or logged out.
+CREATE TABLE refresh_tokens
+(
+    token_hash TEXT                     NOT NULL PRIMARY KEY,
+    nickname   CITEXT                   NOT NULL REFERENCES users (nickname),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX refreshTokensNickname ON refresh_tokens (nickname);
+
+-- Follow the durability of the other tables, see docs/durability.md.
+DO
+$$
+BEGIN
+    IF (SELECT relpersistence FROM pg_class WHERE oid = 'posts'::regclass) = 'u' THEN
+        ALTER TABLE refresh_tokens SET UNLOGGED;
+    END IF;
+END;
+$$;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS forum_moderators;
+DROP TABLE IF EXISTS user_roles;
@@ -0,0 +1,32 @@
+-- Global roles. Admins are granted with the "admin grant" command.
+CREATE TABLE user_roles
+(
+    nickname CITEXT                   NOT NULL REFERENCES users (nickname),
+    role     TEXT                     NOT NULL CHECK (role IN ('admin')),
+    created  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (nickname, role)
+);
+
+-- Moderators of a forum, granted by its owner or an admin. The owner is
+-- forums."user" and isn't listed here.
+CREATE TABLE forum_moderators
+(
+    forum      CITEXT                   NOT NULL REFERENCES forums (slug),
+    nickname   CITEXT                   NOT NULL REFERENCES users (nickname),
+    granted_by CITEXT REFERENCES users (nickname),
+    created    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (forum, nickname)
+);
+
+-- Follow the durability of the other tables, see docs/durability.md.
+DO
+$$
+BEGIN
+    IF (SELECT relpersistence FROM pg_class WHERE oid = 'posts'::regclass) = 'u' THEN
+        ALTER TABLE user_roles SET UNLOGGED;
+        ALTER TABLE forum_moderators SET UNLOGGED;
+    END IF;
+END;
+$$;
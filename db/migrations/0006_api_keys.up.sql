@@ -0,0 +1,29 @@
+-- API keys of bots and service accounts. Only a SHA-256 of the key is
+-- stored; prefix is its first characters, shown to tell keys apart.
+CREATE TABLE api_keys
+(
+    id         BIGSERIAL                NOT NULL PRIMARY KEY,
+    nickname   CITEXT                   NOT NULL REFERENCES users (nickname),
+    name       TEXT                     NOT NULL,
+    prefix     TEXT                     NOT NULL,
+    key_hash   TEXT                     NOT NULL UNIQUE,
+    -- read or write, write includes read.
+    scopes     TEXT[]                   NOT NULL,
+    -- The only forum the key may write to, NULL for any.
+    forum      CITEXT REFERENCES forums (slug),
+    created    TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used  TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX apiKeysNickname ON api_keys (nickname) WHERE revoked_at IS NULL;
+
+-- Follow the durability of the other tables, see docs/durability.md.
+DO
+$$
+BEGIN
+    IF (SELECT relpersistence FROM pg_class WHERE oid = 'posts'::regclass) = 'u' THEN
+        ALTER TABLE api_keys SET UNLOGGED;
+    END IF;
+END;
+$$;
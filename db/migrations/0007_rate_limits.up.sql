@@ -0,0 +1,12 @@
+-- Token buckets of the postgres rate limit backend. The buckets are cheap to
+-- lose, so the table is always unlogged and isn't among the tables the
+-- durability command converts.
+CREATE UNLOGGED TABLE rate_limits
+(
+    key     TEXT                     NOT NULL PRIMARY KEY,
+    tokens  DOUBLE PRECISION         NOT NULL,
+    allowed BOOLEAN                  NOT NULL,
+    updated TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX rateLimitsUpdated ON rate_limits (updated);
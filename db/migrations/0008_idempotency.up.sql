@@ -0,0 +1,30 @@
+-- Responses to requests sent with an Idempotency-Key header, replayed when
+-- the client retries. A row without status is a request still running; it
+-- holds its key until lease_until, so a key claimed by an instance that died
+-- before answering can be claimed again.
+CREATE TABLE idempotency_keys
+(
+    scope        TEXT                     NOT NULL,
+    key          TEXT                     NOT NULL,
+    -- SHA-256 of the method, path and body of the request.
+    fingerprint  TEXT                     NOT NULL,
+    status       INTEGER,
+    content_type TEXT,
+    body         BYTEA,
+    created      TIMESTAMP WITH TIME ZONE NOT NULL,
+    lease_until  TIMESTAMP WITH TIME ZONE,
+
+    PRIMARY KEY (scope, key)
+);
+
+CREATE INDEX idempotencyKeysCreated ON idempotency_keys (created);
+
+-- Follow the durability of the other tables, see docs/durability.md.
+DO
+$$
+BEGIN
+    IF (SELECT relpersistence FROM pg_class WHERE oid = 'posts'::regclass) = 'u' THEN
+        ALTER TABLE idempotency_keys SET UNLOGGED;
+    END IF;
+END;
+$$;
@@ -0,0 +1,28 @@
+DROP INDEX IF EXISTS postsParent;
+
+UPDATE forums f
+SET posts = f.posts + d.count
+FROM (SELECT forum, COUNT(*) AS count FROM posts WHERE deleted_at IS NOT NULL GROUP BY forum) d
+WHERE f.slug = d.forum;
+
+ALTER TABLE posts DROP COLUMN deleted_at;
+
+CREATE OR REPLACE FUNCTION newPath() RETURNS TRIGGER LANGUAGE plpgsql AS
+$$
+DECLARE
+    newPath INTEGER[];
+BEGIN
+    IF NEW.parent IS NULL THEN
+        NEW.path := NEW.path || NEW.id;
+    ELSE
+        SELECT INTO newPath path FROM posts WHERE id = NEW.parent AND thread = NEW.thread;
+
+        IF (newPath[1] IS NULL) THEN
+            RAISE EXCEPTION 'parent empty';
+        END IF;
+
+        NEW.path := NEW.path || newPath || NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$;
@@ -0,0 +1,29 @@
+-- Deleted posts keep their row, so the paths of their replies stay intact,
+-- and are shown as placeholders. forums.posts only counts posts not deleted.
+ALTER TABLE posts ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Finds the replies of a post before removing it for good.
+CREATE INDEX IF NOT EXISTS postsParent ON posts (parent);
+
+-- A reply locks its parent, so a parent can't be purged while a reply to it
+-- is being inserted and a reply can't be inserted under a purged parent.
+-- FOR KEY SHARE doesn't conflict with edits or soft deletes of the parent.
+CREATE OR REPLACE FUNCTION newPath() RETURNS TRIGGER LANGUAGE plpgsql AS
+$$
+DECLARE
+    newPath INTEGER[];
+BEGIN
+    IF NEW.parent IS NULL THEN
+        NEW.path := NEW.path || NEW.id;
+    ELSE
+        SELECT INTO newPath path FROM posts WHERE id = NEW.parent AND thread = NEW.thread FOR KEY SHARE;
+
+        IF (newPath[1] IS NULL) THEN
+            RAISE EXCEPTION 'parent empty';
+        END IF;
+
+        NEW.path := NEW.path || newPath || NEW.id;
+    END IF;
+    RETURN NEW;
+END;
+$$;
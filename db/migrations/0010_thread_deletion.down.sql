@@ -0,0 +1,14 @@
+UPDATE forums f
+SET threads = f.threads + d.threads,
+    posts   = f.posts + d.posts
+FROM (SELECT t.forum,
+             COUNT(DISTINCT t.id)                               AS threads,
+             COUNT(p.id) FILTER (WHERE p.deleted_at IS NULL)    AS posts
+      FROM threads t
+               LEFT JOIN posts p ON p.thread = t.id
+      WHERE t.deleted_at IS NOT NULL
+      GROUP BY t.forum) d
+WHERE f.slug = d.forum;
+
+ALTER TABLE threads DROP COLUMN archived_at;
+ALTER TABLE threads DROP COLUMN deleted_at;
@@ -0,0 +1,5 @@
+-- Deleted threads keep their row until removed for good and are left out of
+-- forums.threads and forums.posts. Archived threads are read-only and only
+-- listed when asked for.
+ALTER TABLE threads ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE threads ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
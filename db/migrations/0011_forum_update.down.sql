@@ -0,0 +1,12 @@
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_forum_fkey,
+    ADD CONSTRAINT api_keys_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE forum_moderators DROP CONSTRAINT forum_moderators_forum_fkey,
+    ADD CONSTRAINT forum_moderators_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE posts DROP CONSTRAINT posts_forum_fkey,
+    ADD CONSTRAINT posts_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE threads DROP CONSTRAINT threads_forum_fkey,
+    ADD CONSTRAINT threads_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+ALTER TABLE forum_users DROP CONSTRAINT forum_users_forum_fkey,
+    ADD CONSTRAINT forum_users_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug);
+
+ALTER TABLE forums DROP COLUMN description;
@@ -0,0 +1,14 @@
+ALTER TABLE forums ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+-- Renaming a forum rewrites its slug in every table referencing it. For big
+-- forums this touches every post, so renames are best done when traffic is low.
+ALTER TABLE forum_users DROP CONSTRAINT forum_users_forum_fkey,
+    ADD CONSTRAINT forum_users_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE threads DROP CONSTRAINT threads_forum_fkey,
+    ADD CONSTRAINT threads_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE posts DROP CONSTRAINT posts_forum_fkey,
+    ADD CONSTRAINT posts_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE forum_moderators DROP CONSTRAINT forum_moderators_forum_fkey,
+    ADD CONSTRAINT forum_moderators_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
+ALTER TABLE api_keys DROP CONSTRAINT api_keys_forum_fkey,
+    ADD CONSTRAINT api_keys_forum_fkey FOREIGN KEY (forum) REFERENCES forums (slug) ON UPDATE CASCADE;
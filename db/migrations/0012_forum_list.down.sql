@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS forumsCreated;
+DROP INDEX IF EXISTS forumsTitle;
+
+ALTER TABLE forums DROP COLUMN created;
@@ -0,0 +1,8 @@
+-- Forums created before this migration get its time as their creation time.
+ALTER TABLE forums ADD COLUMN created TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Keyset pagination of GET /api/forum/list. Sorting by threads or posts goes
+-- without an index: the counters change with every new post, and indexing
+-- them would slow the inserts down more than the few forums cost to sort.
+CREATE INDEX IF NOT EXISTS forumsTitle ON forums (title, slug);
+CREATE INDEX IF NOT EXISTS forumsCreated ON forums (created, slug);
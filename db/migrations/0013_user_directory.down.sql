@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS postsAuthorCreated;
+DROP INDEX IF EXISTS threadsAuthorCreated;
+
+DROP INDEX IF EXISTS usersFullnameTrgm;
+DROP INDEX IF EXISTS usersNicknameTrgm;
@@ -0,0 +1,10 @@
+-- pg_trgm backs the prefix and fuzzy search of GET /api/user/list.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS usersNicknameTrgm ON users USING gin ((nickname::text) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS usersFullnameTrgm ON users USING gin (fullname gin_trgm_ops);
+
+-- When the user last started a thread or posted is read from the threads
+-- and posts themselves, newest first per author.
+CREATE INDEX IF NOT EXISTS threadsAuthorCreated ON threads (author, created);
+CREATE INDEX IF NOT EXISTS postsAuthorCreated ON posts (author, created);
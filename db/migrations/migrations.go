@@ -0,0 +1,312 @@
+// Package migrations applies the versioned schema changes stored next to it
+// as NNNN_name.up.sql / NNNN_name.down.sql pairs. Applied versions and the
+// checksums of their up scripts are recorded in schema_migrations, and every
+// run holds a PostgreSQL advisory lock so concurrent instances wait for each
+// other instead of racing.
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"db_project/utils/constants"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//go:embed *.sql
+var files embed.FS
+
+// lockKey identifies the advisory lock taken while migrating.
+const lockKey int64 = 0x666f72756d6d6967
+
+var fileNameReg = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type State struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the applied up script differs from the embedded one.
+	Modified bool
+	// Unknown is set for versions recorded in the database but missing in the binary.
+	Unknown bool
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+func CreateMigrator(db *pgxpool.Pool) (*Migrator, error) {
+	migrations, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the embedded migrations ordered by version.
+func Load() ([]Migration, error) {
+	entries, err := files.ReadDir(".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := fileNameReg.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file name %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files with different names", version)
+		}
+
+		content, err := files.ReadFile(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d must have both up and down scripts", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies all pending migrations. It refuses to run when an applied
+// migration was modified after it had been applied.
+func (m *Migrator) Up(ctx context.Context) (applied []Migration, err error) {
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		states, err := m.states(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err = checkStates(states); err != nil {
+			return err
+		}
+
+		for _, state := range states {
+			if state.Applied {
+				continue
+			}
+			err = conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, state.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, constants.MigrationQuery["Insert"], state.Version, state.Name, state.Checksum)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %04d_%s failed: %w", state.Version, state.Name, err)
+			}
+			applied = append(applied, state.Migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Down reverts the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []Migration, err error) {
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		states, err := m.states(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err = checkStates(states); err != nil {
+			return err
+		}
+
+		for i := len(states) - 1; i >= 0 && len(reverted) < steps; i-- {
+			state := states[i]
+			if !state.Applied {
+				continue
+			}
+			err = conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, state.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, constants.MigrationQuery["Delete"], state.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("reverting migration %04d_%s failed: %w", state.Version, state.Name, err)
+			}
+			reverted = append(reverted, state.Migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Status reports every known migration, embedded or recorded in the database.
+func (m *Migrator) Status(ctx context.Context) (states []State, err error) {
+	err = m.locked(ctx, func(conn *pgxpool.Conn) error {
+		states, err = m.states(ctx, conn)
+		return err
+	})
+	return
+}
+
+// Current reports whether every embedded migration is applied unmodified.
+// Unlike Status it does not take the lock and does not create schema_migrations.
+func (m *Migrator) Current(ctx context.Context) (current bool, err error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Release()
+
+	var exists bool
+	if err = conn.QueryRow(ctx, constants.MigrationQuery["TableExists"]).Scan(&exists); err != nil || !exists {
+		return
+	}
+
+	states, err := m.applied(ctx, conn)
+	if err != nil {
+		return
+	}
+	for _, state := range states {
+		if !state.Applied || state.Modified || state.Unknown {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func checkStates(states []State) error {
+	for _, state := range states {
+		if state.Modified {
+			return fmt.Errorf("migration %04d_%s was modified after it had been applied", state.Version, state.Name)
+		}
+		if state.Unknown {
+			return fmt.Errorf("database has migration %04d_%s that this binary does not know", state.Version, state.Name)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) locked(ctx context.Context, f func(conn *pgxpool.Conn) error) (err error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, constants.MigrationQuery["Lock"], lockKey); err != nil {
+		return
+	}
+	defer func() {
+		_, unlockErr := conn.Exec(context.Background(), constants.MigrationQuery["Unlock"], lockKey)
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+
+	return f(conn)
+}
+
+// states creates schema_migrations on first use. A database created by the
+// former db/db.sql script already has the initial schema, so it is recorded
+// as migration 1 instead of being created again.
+func (m *Migrator) states(ctx context.Context, conn *pgxpool.Conn) (states []State, err error) {
+	var exists bool
+	if err = conn.QueryRow(ctx, constants.MigrationQuery["TableExists"]).Scan(&exists); err != nil {
+		return
+	}
+
+	if !exists {
+		var legacy bool
+		if err = conn.QueryRow(ctx, constants.MigrationQuery["LegacySchema"]).Scan(&legacy); err != nil {
+			return
+		}
+		if _, err = conn.Exec(ctx, constants.MigrationQuery["CreateTable"]); err != nil {
+			return
+		}
+		if legacy && len(m.migrations) > 0 {
+			first := m.migrations[0]
+			if _, err = conn.Exec(ctx, constants.MigrationQuery["Insert"], first.Version, first.Name, first.Checksum); err != nil {
+				return
+			}
+		}
+	}
+
+	return m.applied(ctx, conn)
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (states []State, err error) {
+	rows, err := conn.Query(ctx, constants.MigrationQuery["Applied"])
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	recorded := make(map[int]State)
+	for rows.Next() {
+		state := State{Applied: true}
+		if err = rows.Scan(&state.Version, &state.Name, &state.Checksum, &state.AppliedAt); err != nil {
+			return nil, err
+		}
+		recorded[state.Version] = state
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		state := State{Migration: migration}
+		if applied, ok := recorded[migration.Version]; ok {
+			state.Applied = true
+			state.AppliedAt = applied.AppliedAt
+			state.Modified = applied.Checksum != migration.Checksum
+			delete(recorded, migration.Version)
+		}
+		states = append(states, state)
+	}
+	for _, unknown := range recorded {
+		unknown.Unknown = true
+		states = append(states, unknown)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Version < states[j].Version
+	})
+
+	return
+}
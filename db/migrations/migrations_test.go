@@ -0,0 +1,76 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestLoadOrdersMigrationsByVersion(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Load() returned no migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %d has version %d, versions must start at 1 without gaps", i, migration.Version)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %04d_%s has an empty script", migration.Version, migration.Name)
+		}
+	}
+}
+
+func TestLoadChecksumsUpScripts(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	for _, migration := range migrations {
+		sum := sha256.Sum256([]byte(migration.Up))
+		if want := hex.EncodeToString(sum[:]); migration.Checksum != want {
+			t.Errorf("migration %04d_%s checksum = %s, want %s", migration.Version, migration.Name, migration.Checksum, want)
+		}
+	}
+}
+
+func TestCheckStates(t *testing.T) {
+	tests := []struct {
+		name    string
+		states  []State
+		wantErr string
+	}{
+		{
+			name:   "applied and pending",
+			states: []State{{Migration: Migration{Version: 1}, Applied: true}, {Migration: Migration{Version: 2}}},
+		},
+		{
+			name:    "modified",
+			states:  []State{{Migration: Migration{Version: 1, Name: "init"}, Applied: true, Modified: true}},
+			wantErr: "0001_init was modified",
+		},
+		{
+			name:    "unknown",
+			states:  []State{{Migration: Migration{Version: 7, Name: "later"}, Applied: true, Unknown: true}},
+			wantErr: "0007_later that this binary does not know",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkStates(test.states)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("checkStates() error = %v, want nil", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("checkStates() error = %v, want it to contain %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -2,13 +2,33 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"db_project/app/handlers"
+	"db_project/app/metrics"
+	"db_project/app/middleware"
 	"db_project/app/repositories"
+	"db_project/app/tracing"
 	"db_project/app/usecases"
-	"db_project/utils/queryCheck"
+	"db_project/db/migrations"
+	"db_project/utils/auth"
+	"db_project/utils/config"
+	"db_project/utils/health"
+	"db_project/utils/i18n"
+	"db_project/utils/idempotency"
+	applog "db_project/utils/logger"
+	"db_project/utils/ratelimit"
+	"errors"
+	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 type Urls struct {
@@ -18,6 +38,7 @@ type Urls struct {
 	Thread  string
 	Service string
 	Post    string
+	Auth    string
 }
 
 func GetUrls() Urls {
@@ -28,15 +49,20 @@ func GetUrls() Urls {
 		Thread:  "/thread",
 		Service: "/service",
 		Post:    "/post",
+		Auth:    "/auth",
 	}
 }
 
 type Repositories struct {
-	User    repositories.IUserRepository
-	Forum   repositories.IForumRepository
-	Thread  repositories.IThreadRepository
-	Service repositories.IServiceRepository
-	Post    repositories.IPostRepository
+	User        repositories.IUserRepository
+	Forum       repositories.IForumRepository
+	Thread      repositories.IThreadRepository
+	Service     repositories.IServiceRepository
+	Post        repositories.IPostRepository
+	Auth        repositories.IAuthRepository
+	Role        repositories.IRoleRepository
+	APIKey      repositories.IAPIKeyRepository
+	Idempotency idempotency.Store
 }
 
 type UseCases struct {
@@ -45,87 +71,295 @@ type UseCases struct {
 	Thread  usecases.IThreadUseCase
 	Service usecases.IServiceUseCase
 	Post    usecases.IPostUseCase
+	Auth    usecases.IAuthUseCase
+	Role    usecases.IRoleUseCase
+	APIKey  usecases.IAPIKeyUseCase
 }
 
 func main() {
-	APIPort := "5000"
-	DSN := "host=localhost port=5432 user=forum_user password=forum_user_password dbname=forum sslmode=disable"
+	cfg, args, err := config.Load(os.Args[1:])
+	if err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Printf("Can't load config: %v\n", err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	logger, err := applog.CreateLogger(os.Stdout, cfg.Log.Format, cfg.Log.Level)
+	if err != nil {
+		fmt.Printf("Can't create logger: %v\n", err)
+		os.Exit(2)
+	}
+
 	Urls := GetUrls()
-	APIAddr := fmt.Sprintf("0.0.0.0:%v", APIPort)
+	APIAddr := cfg.ListenAddr()
 	Repositories := Repositories{}
 	UseCases := UseCases{}
 
-	_, err := queryCheck.GetInstance()
+	poolConfig, err := pgxpool.ParseConfig(cfg.Database.DSN)
 	if err != nil {
-		fmt.Printf("Can't create queryCheck instance: %v", err)
+		logger.Error("can't parse DSN", slog.String("error", err.Error()))
+		return
+	}
+	poolConfig.MinConns = cfg.Database.MinConns
+	poolConfig.MaxConns = cfg.Database.MaxConns
+	poolConfig.ConnConfig.ConnectTimeout = cfg.Database.ConnectTimeout.Std()
+
+	db, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
+	if err != nil {
+		logger.Error("can't create DB connection pool", slog.String("error", err.Error()))
+		return
+	}
+	defer db.Close()
+
+	if len(args) > 0 {
+		if err = runCommand(context.Background(), db, logger, args); err != nil {
+			logger.Error("command failed", slog.String("error", err.Error()))
+			db.Close()
+			os.Exit(1)
+		}
 		return
 	}
 
-	config, err := pgxpool.ParseConfig(DSN)
-	config.MaxConns = 2000
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		ServiceName: cfg.Tracing.ServiceName,
+		Exporter:    cfg.Tracing.Exporter,
+		File:        cfg.Tracing.File,
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
-		fmt.Printf("Can't parese DSN: %v\n", err)
+		logger.Error("can't set up tracing", slog.String("error", err.Error()))
 		return
 	}
+	// Deferred so the spans buffered before any return are flushed, including
+	// when the listener fails. It runs after the requests are drained.
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("can't flush traces", slog.String("error", err.Error()))
+		}
+	}()
 
-	db, err := pgxpool.ConnectConfig(context.Background(), config)
+	catalog, err := i18n.Load(cfg.Locale.Dir, cfg.Locale.DefaultLanguage)
 	if err != nil {
-		fmt.Printf("Can't create DB connection pool: %v", err)
+		logger.Error("can't load message catalogs", slog.String("error", err.Error()))
 		return
 	}
 
-	gin.SetMode("release")
+	secret := []byte(cfg.Auth.Secret)
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		if _, err = rand.Read(secret); err != nil {
+			logger.Error("can't generate auth secret", slog.String("error", err.Error()))
+			return
+		}
+		logger.Warn("auth.secret is not set, access tokens won't survive a restart")
+	}
+	tokens := auth.CreateTokens(secret, cfg.Auth.AccessTTL.Std())
+
+	readiness := health.CreateReadiness()
+
+	gin.SetMode(cfg.Server.GinMode)
 	router := gin.New()
-	router.Use(gin.Recovery())
-	apiGroup := router.Group(Urls.Root)
+	if err = router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Error("can't set trusted proxies", slog.String("error", err.Error()))
+		return
+	}
+	router.Use(middleware.RequestID())
+	router.Use(middleware.AccessLog(logger))
+	router.Use(middleware.Tracing())
+	router.Use(middleware.Recovery(logger))
+
+	var queryHooks []repositories.QueryHook
+	if cfg.Tracing.Exporter != tracing.ExporterNone {
+		queryHooks = append(queryHooks, tracing.CreateQueryHook())
+	}
+	if cfg.Log.SlowQueryThreshold > 0 {
+		queryHooks = append(queryHooks, repositories.CreateSlowQueryLog(logger, cfg.Log.SlowQueryThreshold.Std()))
+	}
+	if cfg.Metrics.Enabled {
+		appMetrics := metrics.CreateMetrics()
+		appMetrics.RegisterPool(db)
+		queryHooks = append(queryHooks, appMetrics)
+		router.Use(middleware.Metrics(appMetrics))
+		router.GET(cfg.Metrics.Path, gin.WrapH(appMetrics.Handler()))
+	}
+
+	router.Use(middleware.Timeout(cfg.Server.RequestTimeout.Std(), cfg.RouteTimeouts()))
+	router.Use(middleware.Errors(logger, middleware.ErrorOptions{
+		Problem:  cfg.Server.ErrorFormat == "problem",
+		TypeBase: cfg.Server.ProblemTypeBase,
+		Catalog:  catalog,
+	}))
+	repoDB := repositories.CreateDB(db, queryHooks...)
+
+	Repositories.User = repositories.CreateUserRepository(repoDB, logger)
+	Repositories.Thread = repositories.CreateThreadRepository(repoDB, logger)
+	Repositories.Forum = repositories.CreateForumRepository(repoDB, logger)
+	Repositories.Service = repositories.CreateServiceRepository(repoDB, logger)
+	Repositories.Post = repositories.CreatePostRepository(repoDB, logger)
+	Repositories.Auth = repositories.CreateAuthRepository(repoDB, logger)
+	Repositories.Role = repositories.CreateRoleRepository(repoDB, logger)
+	Repositories.APIKey = repositories.CreateAPIKeyRepository(repoDB, logger)
+	Repositories.Idempotency = repositories.CreateIdempotencyRepository(repoDB, logger)
 
-	Repositories.User = repositories.CreateUserRepository(db)
-	Repositories.Thread = repositories.CreateThreadRepository(db)
-	Repositories.Forum = repositories.CreateForumRepository(db)
-	Repositories.Service = repositories.CreateServiceRepository(db)
-	Repositories.Post = repositories.CreatePostRepository(db)
+	durability, err := Repositories.Service.Durability(context.Background())
+	if err != nil {
+		logger.Error("can't check table durability", slog.String("error", err.Error()))
+		return
+	}
+	if cfg.Database.Durability != "" && durability != cfg.Database.Durability {
+		logger.Error("table durability differs from the configured one, run the durability command to convert the tables",
+			slog.String("durability", durability), slog.String("configured", cfg.Database.Durability))
+		return
+	}
+	logger.Info("tables checked", slog.String("durability", durability))
+
+	migrator, err := migrations.CreateMigrator(db)
+	if err != nil {
+		logger.Error("can't load migrations", slog.String("error", err.Error()))
+		return
+	}
+
+	UseCases.Role = usecases.CreateRoleUseCase(Repositories.Role, Repositories.Forum, cfg.Auth.AnonymousEdits, logger)
+	UseCases.Forum = usecases.CreateForumUseCase(Repositories.Forum, Repositories.Thread, Repositories.Service, UseCases.Role, logger)
+	UseCases.User = usecases.CreateUserUseCase(Repositories.User, Repositories.Auth, UseCases.Role, logger)
+	UseCases.Thread = usecases.CreateThreadUseCase(Repositories.Thread, Repositories.Service, UseCases.Role, logger)
+	UseCases.Service = usecases.CreateServiceUseCase(Repositories.Service, UseCases.Thread, UseCases.Role, migrator, logger)
+	UseCases.Post = usecases.CreatePostUseCase(Repositories.Post, UseCases.Forum, UseCases.User, UseCases.Thread, UseCases.Role, logger)
+	UseCases.Auth = usecases.CreateAuthUseCase(Repositories.Auth, tokens, cfg.Auth.RefreshTTL.Std(), logger)
+	UseCases.APIKey = usecases.CreateAPIKeyUseCase(Repositories.APIKey, UseCases.Role, logger)
+
+	// Authentication needs the API keys, so the API routes are only set up now.
+	router.Use(middleware.Authenticate(tokens, UseCases.APIKey))
+	if cfg.RateLimit.Enabled {
+		var store ratelimit.Store = ratelimit.CreateMemory()
+		if cfg.RateLimit.Backend == "postgres" {
+			store = repositories.CreateRateLimitRepository(repoDB, logger)
+		}
+		budgets := make(map[string]ratelimit.Budget)
+		for name, budget := range cfg.RateLimit.Budgets() {
+			budgets[name] = ratelimit.Budget{Rate: budget.Rate, Burst: budget.Burst}
+		}
+		router.Use(middleware.RateLimit(ratelimit.CreateLimiter(store, budgets), map[string]string{
+			"POST " + Urls.Root + Urls.Thread + "/:slug_or_id/vote": "vote",
+		}, logger))
+	}
+	requireUser := middleware.RequireUser(cfg.Auth.Required)
+	idempotent := func(c *gin.Context) { c.Next() }
+	if cfg.Idempotency.Enabled {
+		idempotent = middleware.Idempotency(Repositories.Idempotency, cfg.Idempotency.TTL.Std(), cfg.Idempotency.Lease.Std(), logger)
+	}
+	apiGroup := router.Group(Urls.Root)
 
-	UseCases.User = usecases.CreateUserUseCase(Repositories.User)
-	UseCases.Thread = usecases.CreateThreadUseCase(Repositories.Thread)
-	UseCases.Forum = usecases.CreateForumUseCase(Repositories.Forum, Repositories.Thread)
-	UseCases.Service = usecases.CreateServiceUseCase(Repositories.Service)
-	UseCases.Post = usecases.CreatePostUseCase(Repositories.Post, UseCases.Forum, UseCases.User, UseCases.Thread)
+	authHandler := handlers.MakeAuthHandler(UseCases.Auth, logger)
+	authRouter := apiGroup.Group(Urls.Auth)
+	authRouter.POST("/login", authHandler.Login)
+	authRouter.POST("/refresh", authHandler.Refresh)
+	authRouter.POST("/logout", authHandler.Logout)
 
-	userHandler := handlers.MakeUsersHandler(UseCases.User)
+	userHandler := handlers.MakeUsersHandler(UseCases.User, logger)
 	userRouter := apiGroup.Group(Urls.User)
+	userRouter.GET("/list", userHandler.All)
 	userRouter.GET("/:nickname/profile", userHandler.Get)
-	userRouter.POST("/:nickname/profile", userHandler.Update)
-	userRouter.POST("/:nickname/create", userHandler.Create)
+	userRouter.POST("/:nickname/profile", requireUser, userHandler.Update)
+	userRouter.POST("/:nickname/create", idempotent, userHandler.Create)
+
+	apiKeyHandler := handlers.MakeAPIKeysHandler(UseCases.APIKey, logger)
+	userRouter.GET("/:nickname/keys", middleware.RequireUser(true), apiKeyHandler.List)
+	userRouter.POST("/:nickname/keys", middleware.RequireUser(true), apiKeyHandler.Create)
+	userRouter.DELETE("/:nickname/keys/:id", middleware.RequireUser(true), apiKeyHandler.Revoke)
 
-	forumHandler := handlers.MakeForumsHandler(UseCases.Forum)
+	forumHandler := handlers.MakeForumsHandler(UseCases.Forum, logger)
 	forumRouter := apiGroup.Group(Urls.Forum)
+	forumRouter.GET("/list", forumHandler.List)
 	forumRouter.GET("/:slug/details", forumHandler.Get)
-	forumRouter.POST("/create", forumHandler.Create)
+	forumRouter.POST("/create", requireUser, idempotent, forumHandler.Create)
 	forumRouter.GET("/:slug/users", forumHandler.GetUsers)
 	forumRouter.GET("/:slug/threads", forumHandler.GetThreads)
-	forumRouter.POST("/:slug/create", forumHandler.CreateThread)
+	forumRouter.POST("/:slug/create", requireUser, idempotent, forumHandler.CreateThread)
+	forumRouter.POST("/:slug/details", middleware.RequireUser(true), forumHandler.Update)
+	forumRouter.DELETE("/:slug", middleware.RequireUser(true), forumHandler.Delete)
 
-	threadHandler := handlers.MakeThreadsHandler(UseCases.Thread)
+	roleHandler := handlers.MakeRolesHandler(UseCases.Role, logger)
+	forumRouter.GET("/:slug/moderators", roleHandler.Moderators)
+	forumRouter.POST("/:slug/moderators/:nickname", middleware.RequireUser(true), roleHandler.GrantModerator)
+	forumRouter.DELETE("/:slug/moderators/:nickname", middleware.RequireUser(true), roleHandler.RevokeModerator)
+
+	threadHandler := handlers.MakeThreadsHandler(UseCases.Thread, logger)
 	threadRouter := apiGroup.Group(Urls.Thread)
 	threadRouter.GET("/:slug_or_id/details", threadHandler.Get)
-	threadRouter.POST("/:slug_or_id/details", threadHandler.Update)
-	threadRouter.POST("/:slug_or_id/vote", threadHandler.Vote)
-	threadRouter.POST("/:slug_or_id/create", threadHandler.PostsCreate)
+	threadRouter.POST("/:slug_or_id/details", requireUser, threadHandler.Update)
+	threadRouter.POST("/:slug_or_id/vote", requireUser, idempotent, threadHandler.Vote)
+	threadRouter.POST("/:slug_or_id/create", requireUser, idempotent, threadHandler.PostsCreate)
 	threadRouter.GET("/:slug_or_id/posts", threadHandler.GetPosts)
+	threadRouter.DELETE("/:slug_or_id", middleware.RequireUser(true), threadHandler.Delete)
+	threadRouter.POST("/:slug_or_id/restore", middleware.RequireUser(true), threadHandler.Restore)
+	threadRouter.POST("/:slug_or_id/archive", middleware.RequireUser(true), threadHandler.Archive)
+	threadRouter.DELETE("/:slug_or_id/archive", middleware.RequireUser(true), threadHandler.Unarchive)
 
-	serviceHandler := handlers.MakeServicesHandler(UseCases.Service)
+	serviceHandler := handlers.MakeServicesHandler(UseCases.Service, readiness, logger)
 	serviceRouter := apiGroup.Group(Urls.Service)
-	serviceRouter.POST("/clear", serviceHandler.Clear)
+	if cfg.Admin.ClearEnabled {
+		serviceRouter.POST("/clear", middleware.RequireUser(true), serviceHandler.Clear)
+	} else {
+		serviceRouter.POST("/clear", serviceHandler.ClearDisabled)
+	}
 	serviceRouter.GET("/status", serviceHandler.Status)
+	serviceRouter.GET("/health/live", serviceHandler.Live)
+	serviceRouter.GET("/health/ready", serviceHandler.Ready)
+	serviceRouter.GET("/health/deep", serviceHandler.Deep)
 
-	postHandler := handlers.MakePostsHandler(UseCases.Post)
+	postHandler := handlers.MakePostsHandler(UseCases.Post, logger)
 	postRouter := apiGroup.Group(Urls.Post)
 	postRouter.GET("/:id/details", postHandler.Get)
-	postRouter.POST("/:id/details", postHandler.Update)
+	postRouter.POST("/:id/details", requireUser, postHandler.Update)
+	postRouter.DELETE("/:id", middleware.RequireUser(true), postHandler.Delete)
+	postRouter.POST("/:id/restore", middleware.RequireUser(true), postHandler.Restore)
+
+	server := &http.Server{
+		Addr:    APIAddr,
+		Handler: router,
+	}
+
+	stopCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-	err = router.Run(APIAddr)
+	// Readiness is only reported once the address is bound, so a server that
+	// can't listen never looks ready.
+	listener, err := net.Listen("tcp", APIAddr)
 	if err != nil {
-		fmt.Printf("Can't start server: %v\n", err)
+		logger.Error("can't start server", slog.String("error", err.Error()))
 		return
 	}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.Serve(listener)
+	}()
+	readiness.SetReady(true)
+	logger.Info("server started", slog.String("addr", APIAddr))
+
+	select {
+	case err = <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("can't start server", slog.String("error", err.Error()))
+		}
+		return
+	case <-stopCtx.Done():
+	}
+
+	logger.Info("shutting down")
+	readiness.SetReady(false)
+	time.Sleep(cfg.Server.ShutdownDelay.Std())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
+	defer cancel()
+	if err = server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("can't drain requests in time",
+			slog.Duration("timeout", cfg.Server.ShutdownTimeout.Std()), slog.String("error", err.Error()))
+	}
 }
@@ -0,0 +1,182 @@
+// Package auth hashes passwords and issues the tokens users authenticate
+// with: short-lived access tokens, which are HS256-signed JWTs naming the
+// user, long-lived opaque refresh tokens and API keys for bots. Only hashes
+// of refresh tokens and API keys are stored.
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+type userKey struct{}
+
+// WithUser marks ctx as authenticated as nickname.
+func WithUser(ctx context.Context, nickname string) context.Context {
+	return context.WithValue(ctx, userKey{}, nickname)
+}
+
+// User returns the nickname ctx is authenticated as, or "" for anonymous requests.
+func User(ctx context.Context) string {
+	nickname, _ := ctx.Value(userKey{}).(string)
+	return nickname
+}
+
+// APIKeyPrefix starts every API key, so leaked keys are easy to grep for.
+const APIKeyPrefix = "fk_"
+
+// APIKey is the key a request authenticated with.
+type APIKey struct {
+	ID       int64
+	Nickname string
+	// ReadOnly keys may only read. Forum, if set, is the only forum the key may write to.
+	ReadOnly bool
+	Forum    string
+}
+
+type apiKeyKey struct{}
+
+// WithAPIKey marks ctx as authenticated with key as the user owning it.
+func WithAPIKey(ctx context.Context, key *APIKey) context.Context {
+	return context.WithValue(WithUser(ctx, key.Nickname), apiKeyKey{}, key)
+}
+
+// Key returns the API key ctx is authenticated with, or nil for requests
+// without one.
+func Key(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyKey{}).(*APIKey)
+	return key
+}
+
+// NewAPIKey returns a random API key, the prefix shown to identify it and
+// the hash to store.
+func NewAPIKey() (key string, prefix string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return
+	}
+	key = APIKeyPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	prefix = key[:len(APIKeyPrefix)+6]
+	hash = HashToken(key)
+	return
+}
+
+// dummyHash is compared against when a user has no password, so that
+// unknown users take as long to reject as wrong passwords.
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
+
+// CheckPassword reports whether password matches hash. An empty hash never matches.
+func CheckPassword(hash string, password string) bool {
+	if hash == "" {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+// NewRefreshToken returns a random refresh token and the hash to store.
+func NewRefreshToken() (token string, hash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return
+	}
+	token = base64.RawURLEncoding.EncodeToString(buf)
+	hash = HashToken(token)
+	return
+}
+
+// HashToken returns the hash stored for a refresh token or an API key. They
+// are random and long, so a fast hash is enough.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Tokens issues and verifies access tokens.
+type Tokens struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func CreateTokens(secret []byte, ttl time.Duration) *Tokens {
+	return &Tokens{secret: secret, ttl: ttl}
+}
+
+func (t *Tokens) TTL() time.Duration {
+	return t.ttl
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+}
+
+type claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+var encodedHeader = encodeSegment(header{Alg: "HS256", Typ: "JWT"})
+
+// Issue returns an access token for nickname valid for the configured TTL.
+func (t *Tokens) Issue(nickname string) string {
+	now := time.Now()
+	payload := encodedHeader + "." + encodeSegment(claims{
+		Subject:   nickname,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(t.ttl).Unix(),
+	})
+	return payload + "." + t.sign(payload)
+}
+
+// Parse verifies an access token and returns the nickname it was issued to.
+func (t *Tokens) Parse(token string) (nickname string, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != encodedHeader {
+		return "", ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(parts[2]), []byte(t.sign(parts[0]+"."+parts[1]))) {
+		return "", ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidToken
+	}
+	var c claims
+	if err = json.Unmarshal(data, &c); err != nil || c.Subject == "" {
+		return "", ErrInvalidToken
+	}
+	if time.Now().Unix() >= c.ExpiresAt {
+		return "", ErrInvalidToken
+	}
+	return c.Subject, nil
+}
+
+func (t *Tokens) sign(payload string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodeSegment(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,87 @@
+package auth
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("0123456789abcdef0123456789abcdef")
+
+// forge builds a token from a raw header and claims, signed with key.
+func forge(key []byte, rawHeader string, rawClaims string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(rawHeader)) + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(rawClaims))
+	return payload + "." + CreateTokens(key, time.Minute).sign(payload)
+}
+
+func TestTokensParse(t *testing.T) {
+	tokens := CreateTokens(secret, time.Minute)
+	valid := tokens.Issue("alice")
+	parts := strings.Split(valid, ".")
+	future := time.Now().Add(time.Hour).Unix()
+	exp := strconv.FormatInt(future, 10)
+	claims := `{"sub":"alice","iat":0,"exp":` + exp + `}`
+
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"valid", valid, "alice"},
+		{"tampered signature", parts[0] + "." + parts[1] + "." + strings.Repeat("A", len(parts[2])), ""},
+		{"tampered claims", parts[0] + "." + encodeSegment(map[string]interface{}{"sub": "root", "exp": future}) + "." + parts[2], ""},
+		{"other secret", CreateTokens([]byte("fedcba9876543210fedcba9876543210"), time.Minute).Issue("alice"), ""},
+		{"alg none", forge(secret, `{"alg":"none","typ":"JWT"}`, claims), ""},
+		{"alg HS512", forge(secret, `{"alg":"HS512","typ":"JWT"}`, claims), ""},
+		{"unsigned", parts[0] + "." + parts[1] + ".", ""},
+		{"expired", CreateTokens(secret, -time.Second).Issue("alice"), ""},
+		{"no subject", forge(secret, `{"alg":"HS256","typ":"JWT"}`, `{"exp":`+exp+`}`), ""},
+		{"not a token", "alice", ""},
+		{"empty", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nickname, err := tokens.Parse(test.token)
+			if test.want == "" {
+				if err != ErrInvalidToken {
+					t.Errorf("Parse() = %q, %v, want %v", nickname, err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil || nickname != test.want {
+				t.Errorf("Parse() = %q, %v, want %q", nickname, err, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse")
+	if err != nil {
+		t.Fatalf("HashPassword() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     bool
+	}{
+		{"right password", hash, "correct horse", true},
+		{"wrong password", hash, "correct horse battery", false},
+		{"no password set", "", "", false},
+		{"no password set, any given", "", "correct horse", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CheckPassword(test.hash, test.password); got != test.want {
+				t.Errorf("CheckPassword() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
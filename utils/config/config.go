@@ -0,0 +1,387 @@
+// Package config loads the service settings.
+//
+// Values are resolved in the following order, later sources overriding
+// earlier ones:
+//
+//  1. built-in defaults (see Default)
+//  2. the config file given by -config or FORUM_CONFIG (.toml, .yaml or .yml)
+//  3. FORUM_* environment variables
+//  4. command line flags
+//
+// The resulting configuration is validated before it is returned, so a
+// misconfigured instance fails at startup instead of at the first request.
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Config struct {
+	Server      ServerConfig      `toml:"server" yaml:"server"`
+	Database    DatabaseConfig    `toml:"database" yaml:"database"`
+	Metrics     MetricsConfig     `toml:"metrics" yaml:"metrics"`
+	Log         LogConfig         `toml:"log" yaml:"log"`
+	Tracing     TracingConfig     `toml:"tracing" yaml:"tracing"`
+	Admin       AdminConfig       `toml:"admin" yaml:"admin"`
+	Locale      LocaleConfig      `toml:"locale" yaml:"locale"`
+	Auth        AuthConfig        `toml:"auth" yaml:"auth"`
+	RateLimit   RateLimitConfig   `toml:"rate_limit" yaml:"rate_limit"`
+	Idempotency IdempotencyConfig `toml:"idempotency" yaml:"idempotency"`
+}
+
+type ServerConfig struct {
+	Host    string `toml:"host" yaml:"host"`
+	Port    int    `toml:"port" yaml:"port"`
+	GinMode string `toml:"gin_mode" yaml:"gin_mode"`
+	// ShutdownDelay is how long the instance keeps serving after it has
+	// reported itself not ready, so load balancers can stop routing to it.
+	ShutdownDelay Duration `toml:"shutdown_delay" yaml:"shutdown_delay"`
+	// ShutdownTimeout bounds how long in-flight requests are drained.
+	ShutdownTimeout Duration `toml:"shutdown_timeout" yaml:"shutdown_timeout"`
+	// RequestTimeout is the deadline of a request and the queries it runs.
+	// RouteTimeouts overrides it per route, keyed by "METHOD /route/:pattern".
+	RequestTimeout Duration            `toml:"request_timeout" yaml:"request_timeout"`
+	RouteTimeouts  map[string]Duration `toml:"route_timeouts" yaml:"route_timeouts"`
+	// ErrorFormat is the default error body: "legacy" ({"message": ...}) or
+	// "problem" (RFC 7807). Clients sending Accept: application/problem+json
+	// always get the latter.
+	ErrorFormat string `toml:"error_format" yaml:"error_format"`
+	// ProblemTypeBase is prefixed to the error code to build the problem type URI.
+	ProblemTypeBase string `toml:"problem_type_base" yaml:"problem_type_base"`
+	// TrustedProxies are the IPs and CIDRs of the reverse proxies whose
+	// X-Forwarded-For and X-Real-IP headers give the client IP. Anonymous
+	// clients are rate limited by that IP, so by default no proxy is trusted
+	// and the IP is the one of the connection.
+	TrustedProxies []string `toml:"trusted_proxies" yaml:"trusted_proxies"`
+}
+
+type DatabaseConfig struct {
+	DSN            string   `toml:"dsn" yaml:"dsn"`
+	MinConns       int32    `toml:"min_conns" yaml:"min_conns"`
+	MaxConns       int32    `toml:"max_conns" yaml:"max_conns"`
+	ConnectTimeout Duration `toml:"connect_timeout" yaml:"connect_timeout"`
+	// Durability is the expected storage mode of the tables, "logged" or
+	// "unlogged". The server refuses to start when the database differs.
+	// Empty accepts either mode.
+	Durability string `toml:"durability" yaml:"durability"`
+}
+
+type MetricsConfig struct {
+	Enabled bool   `toml:"enabled" yaml:"enabled"`
+	Path    string `toml:"path" yaml:"path"`
+}
+
+type LogConfig struct {
+	Level  string `toml:"level" yaml:"level"`
+	Format string `toml:"format" yaml:"format"`
+	// SlowQueryThreshold logs queries taking at least this long, 0 disables the log.
+	SlowQueryThreshold Duration `toml:"slow_query_threshold" yaml:"slow_query_threshold"`
+}
+
+type TracingConfig struct {
+	// Exporter is none, stdout, file or otlp.
+	Exporter    string  `toml:"exporter" yaml:"exporter"`
+	File        string  `toml:"file" yaml:"file"`
+	Endpoint    string  `toml:"endpoint" yaml:"endpoint"`
+	Insecure    bool    `toml:"insecure" yaml:"insecure"`
+	SampleRatio float64 `toml:"sample_ratio" yaml:"sample_ratio"`
+	ServiceName string  `toml:"service_name" yaml:"service_name"`
+}
+
+type AdminConfig struct {
+	// ClearEnabled turns on POST /api/service/clear for admins. Keep it off in production.
+	ClearEnabled bool `toml:"clear_enabled" yaml:"clear_enabled"`
+}
+
+type LocaleConfig struct {
+	// DefaultLanguage answers clients whose Accept-Language matches no catalog.
+	DefaultLanguage string `toml:"default_language" yaml:"default_language"`
+	// Dir holds extra <language>.json catalogs, loaded at startup.
+	Dir string `toml:"dir" yaml:"dir"`
+}
+
+type AuthConfig struct {
+	// Secret signs access tokens. When empty a random one is generated at
+	// startup, so tokens don't survive a restart and aren't shared by replicas.
+	Secret     string   `toml:"secret" yaml:"secret"`
+	AccessTTL  Duration `toml:"access_ttl" yaml:"access_ttl"`
+	RefreshTTL Duration `toml:"refresh_ttl" yaml:"refresh_ttl"`
+	// Required rejects anonymous writes. Otherwise anonymous requests may
+	// create and vote, but edits need an author, moderator or admin.
+	Required bool `toml:"required" yaml:"required"`
+	// AnonymousEdits lets anonymous requests edit any profile, thread and
+	// post, as before authentication existed. It only keeps old clients
+	// working and defeats the edit permissions, so it is off by default.
+	AnonymousEdits bool `toml:"anonymous_edits" yaml:"anonymous_edits"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `toml:"enabled" yaml:"enabled"`
+	// Backend is memory, limiting every instance on its own, or postgres,
+	// sharing the limits between the instances using the database.
+	Backend string       `toml:"backend" yaml:"backend"`
+	Read    BudgetConfig `toml:"read" yaml:"read"`
+	Write   BudgetConfig `toml:"write" yaml:"write"`
+	Vote    BudgetConfig `toml:"vote" yaml:"vote"`
+}
+
+type IdempotencyConfig struct {
+	// Enabled honors the Idempotency-Key header on the create endpoints.
+	Enabled bool `toml:"enabled" yaml:"enabled"`
+	// TTL is how long responses are kept for retries.
+	TTL Duration `toml:"ttl" yaml:"ttl"`
+	// Lease is how long a request holds its key while it runs. Retries get
+	// 409 until then; once it expires, e.g. because the instance died, the
+	// key can be claimed again. It should outlast the request timeouts.
+	Lease Duration `toml:"lease" yaml:"lease"`
+}
+
+// BudgetConfig lets a client make Rate requests per second on average and
+// Burst requests at once.
+type BudgetConfig struct {
+	Rate  float64 `toml:"rate" yaml:"rate"`
+	Burst int     `toml:"burst" yaml:"burst"`
+}
+
+// Duration is a time.Duration that is written as "5s", "1m30s" etc. in config files.
+type Duration time.Duration
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var text string
+	if err := unmarshal(&text); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(text))
+}
+
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host:            "0.0.0.0",
+			Port:            5000,
+			GinMode:         "release",
+			ShutdownDelay:   0,
+			ShutdownTimeout: Duration(15 * time.Second),
+			RequestTimeout:  Duration(30 * time.Second),
+			ErrorFormat:     "legacy",
+			ProblemTypeBase: "urn:forum:problem:",
+		},
+		Database: DatabaseConfig{
+			DSN:            "host=localhost port=5432 user=forum_user dbname=forum sslmode=disable",
+			MinConns:       0,
+			MaxConns:       2000,
+			ConnectTimeout: Duration(5 * time.Second),
+		},
+		Metrics: MetricsConfig{
+			Enabled: true,
+			Path:    "/metrics",
+		},
+		Log: LogConfig{
+			Level:              "info",
+			Format:             "json",
+			SlowQueryThreshold: Duration(200 * time.Millisecond),
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			File:        "traces.jsonl",
+			SampleRatio: 1,
+			ServiceName: "forum",
+		},
+		Locale: LocaleConfig{
+			DefaultLanguage: "ru",
+		},
+		Auth: AuthConfig{
+			AccessTTL:  Duration(15 * time.Minute),
+			RefreshTTL: Duration(30 * 24 * time.Hour),
+		},
+		RateLimit: RateLimitConfig{
+			Backend: "memory",
+			Read:    BudgetConfig{Rate: 100, Burst: 200},
+			Write:   BudgetConfig{Rate: 10, Burst: 50},
+			Vote:    BudgetConfig{Rate: 1, Burst: 10},
+		},
+		Idempotency: IdempotencyConfig{
+			Enabled: true,
+			TTL:     Duration(24 * time.Hour),
+			Lease:   Duration(time.Minute),
+		},
+	}
+}
+
+func (cfg *Config) RouteTimeouts() map[string]time.Duration {
+	timeouts := make(map[string]time.Duration, len(cfg.Server.RouteTimeouts))
+	for route, timeout := range cfg.Server.RouteTimeouts {
+		timeouts[route] = timeout.Std()
+	}
+	return timeouts
+}
+
+// Budgets returns the rate limit budgets by name.
+func (cfg *RateLimitConfig) Budgets() map[string]BudgetConfig {
+	return map[string]BudgetConfig{"read": cfg.Read, "write": cfg.Write, "vote": cfg.Vote}
+}
+
+func (cfg *Config) ListenAddr() string {
+	return net.JoinHostPort(cfg.Server.Host, strconv.Itoa(cfg.Server.Port))
+}
+
+// ValidationError lists every invalid setting found by Validate.
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+func (cfg *Config) Validate() error {
+	var problems ValidationError
+
+	if cfg.Server.Port < 1 || cfg.Server.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("server.port must be in 1..65535, got %d", cfg.Server.Port))
+	}
+	switch cfg.Server.GinMode {
+	case "debug", "release", "test":
+	default:
+		problems = append(problems, fmt.Sprintf("server.gin_mode must be debug, release or test, got %q", cfg.Server.GinMode))
+	}
+
+	if cfg.Server.ShutdownDelay < 0 {
+		problems = append(problems, "server.shutdown_delay must not be negative")
+	}
+	if cfg.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server.shutdown_timeout must be positive")
+	}
+
+	if cfg.Server.RequestTimeout < 0 {
+		problems = append(problems, "server.request_timeout must not be negative")
+	}
+	for route, timeout := range cfg.Server.RouteTimeouts {
+		if parts := strings.SplitN(route, " ", 2); len(parts) != 2 || !strings.HasPrefix(parts[1], "/") {
+			problems = append(problems, fmt.Sprintf("server.route_timeouts key %q must look like \"GET /api/...\"", route))
+		}
+		if timeout < 0 {
+			problems = append(problems, fmt.Sprintf("server.route_timeouts[%q] must not be negative", route))
+		}
+	}
+
+	switch cfg.Server.ErrorFormat {
+	case "legacy", "problem":
+	default:
+		problems = append(problems, fmt.Sprintf("server.error_format must be legacy or problem, got %q", cfg.Server.ErrorFormat))
+	}
+	for _, proxy := range cfg.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Sprintf("server.trusted_proxies must be IPs or CIDRs, got %q", proxy))
+		}
+	}
+
+	if strings.TrimSpace(cfg.Database.DSN) == "" {
+		problems = append(problems, "database.dsn must not be empty")
+	}
+	if cfg.Database.MaxConns < 1 {
+		problems = append(problems, fmt.Sprintf("database.max_conns must be positive, got %d", cfg.Database.MaxConns))
+	}
+	if cfg.Database.MinConns < 0 || cfg.Database.MinConns > cfg.Database.MaxConns {
+		problems = append(problems, fmt.Sprintf("database.min_conns must be in 0..max_conns, got %d", cfg.Database.MinConns))
+	}
+	if cfg.Database.ConnectTimeout < 0 {
+		problems = append(problems, "database.connect_timeout must not be negative")
+	}
+	switch cfg.Database.Durability {
+	case "", "logged", "unlogged":
+	default:
+		problems = append(problems, fmt.Sprintf("database.durability must be logged or unlogged, got %q", cfg.Database.Durability))
+	}
+
+	if cfg.Metrics.Enabled && !strings.HasPrefix(cfg.Metrics.Path, "/") {
+		problems = append(problems, fmt.Sprintf("metrics.path must start with /, got %q", cfg.Metrics.Path))
+	}
+
+	switch strings.ToLower(cfg.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("log.level must be debug, info, warn or error, got %q", cfg.Log.Level))
+	}
+	switch strings.ToLower(cfg.Log.Format) {
+	case "json", "text":
+	default:
+		problems = append(problems, fmt.Sprintf("log.format must be json or text, got %q", cfg.Log.Format))
+	}
+	if cfg.Log.SlowQueryThreshold < 0 {
+		problems = append(problems, "log.slow_query_threshold must not be negative")
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "none", "stdout", "otlp":
+	case "file":
+		if cfg.Tracing.File == "" {
+			problems = append(problems, "tracing.file must be set for the file exporter")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("tracing.exporter must be none, stdout, file or otlp, got %q", cfg.Tracing.Exporter))
+	}
+	if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+		problems = append(problems, fmt.Sprintf("tracing.sample_ratio must be in 0..1, got %v", cfg.Tracing.SampleRatio))
+	}
+
+	if strings.TrimSpace(cfg.Locale.DefaultLanguage) == "" {
+		problems = append(problems, "locale.default_language must not be empty")
+	}
+
+	if cfg.Auth.AccessTTL <= 0 {
+		problems = append(problems, "auth.access_ttl must be positive")
+	}
+	if cfg.Auth.RefreshTTL <= 0 {
+		problems = append(problems, "auth.refresh_ttl must be positive")
+	}
+	if cfg.Auth.Secret != "" && len(cfg.Auth.Secret) < 32 {
+		problems = append(problems, "auth.secret must be at least 32 bytes long")
+	}
+	if cfg.Auth.Required && cfg.Auth.Secret == "" {
+		problems = append(problems, "auth.secret must be set when auth.required is true")
+	}
+
+	switch cfg.RateLimit.Backend {
+	case "memory", "postgres":
+	default:
+		problems = append(problems, fmt.Sprintf("rate_limit.backend must be memory or postgres, got %q", cfg.RateLimit.Backend))
+	}
+	budgets := cfg.RateLimit.Budgets()
+	for _, name := range []string{"read", "write", "vote"} {
+		budget := budgets[name]
+		if budget.Rate <= 0 {
+			problems = append(problems, fmt.Sprintf("rate_limit.%s.rate must be positive", name))
+		}
+		if budget.Burst < 1 {
+			problems = append(problems, fmt.Sprintf("rate_limit.%s.burst must be at least 1", name))
+		}
+	}
+
+	if cfg.Idempotency.TTL <= 0 {
+		problems = append(problems, "idempotency.ttl must be positive")
+	}
+	if cfg.Idempotency.Lease <= 0 || cfg.Idempotency.Lease > cfg.Idempotency.TTL {
+		problems = append(problems, "idempotency.lease must be positive and at most idempotency.ttl")
+	}
+
+	if len(problems) > 0 {
+		return problems
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("can't write %s: %v", path, err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "forum.toml", "[server]\nport = 6000\nshutdown_timeout = \"20s\"\n")
+
+	tests := []struct {
+		name string
+		file bool
+		env  string
+		flag string
+		want int
+	}{
+		{"default", false, "", "", 5000},
+		{"file overrides default", true, "", "", 6000},
+		{"env overrides file", true, "7000", "", 7000},
+		{"flag overrides env", true, "7000", "8000", 8000},
+		{"flag without file or env", false, "", "8000", 8000},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("FORUM_CONFIG", "")
+			if test.file {
+				t.Setenv("FORUM_CONFIG", file)
+			}
+			if test.env != "" {
+				t.Setenv("FORUM_SERVER_PORT", test.env)
+			}
+			var args []string
+			if test.flag != "" {
+				args = append(args, "-port", test.flag)
+			}
+
+			cfg, _, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Server.Port != test.want {
+				t.Errorf("server.port = %d, want %d", cfg.Server.Port, test.want)
+			}
+			// Settings no source overrides keep the value of the earlier ones.
+			wantTimeout := 15 * time.Second
+			if test.file {
+				wantTimeout = 20 * time.Second
+			}
+			if cfg.Server.ShutdownTimeout.Std() != wantTimeout {
+				t.Errorf("server.shutdown_timeout = %v, want %v", cfg.Server.ShutdownTimeout.Std(), wantTimeout)
+			}
+		})
+	}
+}
+
+func TestLoadReturnsPositionalArguments(t *testing.T) {
+	t.Setenv("FORUM_CONFIG", "")
+	_, rest, err := Load([]string{"-port", "6000", "migrate", "up"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if want := []string{"migrate", "up"}; !reflect.DeepEqual(rest, want) {
+		t.Errorf("Load() rest = %q, want %q", rest, want)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{"toml", "forum.toml", "[log]\nslow_query_threshold = \"1s\"\n[auth]\nrequired = true\nsecret = \"0123456789abcdef0123456789abcdef\"\n", ""},
+		{"yaml", "forum.yaml", "log:\n  slow_query_threshold: 1s\nauth:\n  required: true\n  secret: 0123456789abcdef0123456789abcdef\n", ""},
+		{"unknown toml field", "forum.toml", "[server]\nprot = 6000\n", "can't parse config file"},
+		{"unknown yaml field", "forum.yml", "server:\n  prot: 6000\n", "can't parse config file"},
+		{"bad duration", "forum.toml", "[log]\nslow_query_threshold = \"soon\"\n", "can't parse config file"},
+		{"unsupported format", "forum.json", "{}", "unsupported config file format"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("FORUM_CONFIG", "")
+			cfg, _, err := Load([]string{"-config", writeFile(t, test.file, test.content)})
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Load() error = %v, want it to mention %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Log.SlowQueryThreshold.Std() != time.Second || !cfg.Auth.Required {
+				t.Errorf("Load() = slow_query_threshold %v, auth.required %v, want 1s, true",
+					cfg.Log.SlowQueryThreshold.Std(), cfg.Auth.Required)
+			}
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("FORUM_CONFIG", filepath.Join(t.TempDir(), "missing.toml"))
+	if _, _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "can't read config file") {
+		t.Errorf("Load() error = %v, want the file to be unreadable", err)
+	}
+}
+
+func TestLoadParsesOverrides(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		args  []string
+		check func(cfg *Config) bool
+	}{
+		{"bool", map[string]string{"FORUM_METRICS_ENABLED": "false"}, nil,
+			func(cfg *Config) bool { return !cfg.Metrics.Enabled }},
+		{"bool with spaces", nil, []string{"-rate-limit", " TRUE "},
+			func(cfg *Config) bool { return cfg.RateLimit.Enabled }},
+		{"duration", map[string]string{"FORUM_SHUTDOWN_TIMEOUT": "1m30s"}, nil,
+			func(cfg *Config) bool { return cfg.Server.ShutdownTimeout.Std() == 90*time.Second }},
+		{"float", nil, []string{"-trace-sample-ratio", "0.25"},
+			func(cfg *Config) bool { return cfg.Tracing.SampleRatio == 0.25 }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("FORUM_CONFIG", "")
+			for name, value := range test.env {
+				t.Setenv(name, value)
+			}
+			cfg, _, err := Load(test.args)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !test.check(cfg) {
+				t.Errorf("Load() didn't apply %v %q", test.env, test.args)
+			}
+		})
+	}
+}
+
+func TestLoadRejectsMalformedOverrides(t *testing.T) {
+	t.Setenv("FORUM_CONFIG", "")
+	t.Setenv("FORUM_METRICS_ENABLED", "maybe")
+	t.Setenv("FORUM_SERVER_PORT", "http")
+
+	_, _, err := Load([]string{"-shutdown-timeout", "soon"})
+	var problems ValidationError
+	if !errors.As(err, &problems) {
+		t.Fatalf("Load() error = %v, want a ValidationError", err)
+	}
+	want := ValidationError{
+		`FORUM_SERVER_PORT: "http" is not an integer`,
+		`FORUM_METRICS_ENABLED: "maybe" is not a boolean`,
+		`-shutdown-timeout: "soon" is not a duration`,
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("Load() problems = %q, want %q", problems, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(cfg *Config)
+		want   string
+	}{
+		{"default", func(cfg *Config) {}, ""},
+		{"port", func(cfg *Config) { cfg.Server.Port = 70000 }, "server.port must be in 1..65535"},
+		{"gin mode", func(cfg *Config) { cfg.Server.GinMode = "prod" }, "server.gin_mode"},
+		{"shutdown timeout", func(cfg *Config) { cfg.Server.ShutdownTimeout = 0 }, "server.shutdown_timeout must be positive"},
+		{"route timeout key", func(cfg *Config) {
+			cfg.Server.RouteTimeouts = map[string]Duration{"/api/forum": Duration(time.Second)}
+		}, "server.route_timeouts key"},
+		{"trusted proxy", func(cfg *Config) { cfg.Server.TrustedProxies = []string{"proxy"} }, "server.trusted_proxies"},
+		{"min conns", func(cfg *Config) { cfg.Database.MinConns = cfg.Database.MaxConns + 1 }, "database.min_conns"},
+		{"durability", func(cfg *Config) { cfg.Database.Durability = "durable" }, "database.durability"},
+		{"metrics path", func(cfg *Config) { cfg.Metrics.Path = "metrics" }, "metrics.path"},
+		{"trace file", func(cfg *Config) { cfg.Tracing.Exporter, cfg.Tracing.File = "file", "" }, "tracing.file"},
+		{"sample ratio", func(cfg *Config) { cfg.Tracing.SampleRatio = 2 }, "tracing.sample_ratio"},
+		{"short secret", func(cfg *Config) { cfg.Auth.Secret = "secret" }, "auth.secret must be at least 32 bytes"},
+		{"required without secret", func(cfg *Config) { cfg.Auth.Required = true }, "auth.secret must be set"},
+		{"rate limit burst", func(cfg *Config) { cfg.RateLimit.Write.Burst = 0 }, "rate_limit.write.burst"},
+		{"idempotency lease", func(cfg *Config) { cfg.Idempotency.Lease = cfg.Idempotency.TTL + 1 }, "idempotency.lease"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Default()
+			test.change(cfg)
+			err := cfg.Validate()
+			if test.want == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Validate() error = %v, want it to mention %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateListsEveryProblem(t *testing.T) {
+	cfg := Default()
+	cfg.Server.Port = 0
+	cfg.Log.Level = "loud"
+	cfg.RateLimit.Backend = "redis"
+
+	var problems ValidationError
+	if !errors.As(cfg.Validate(), &problems) || len(problems) != 3 {
+		t.Errorf("Validate() = %q, want the 3 problems", problems)
+	}
+}
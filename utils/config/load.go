@@ -0,0 +1,302 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "FORUM_"
+
+// option describes a setting that can be overridden by an environment
+// variable (envPrefix + env) and by a command line flag.
+type option struct {
+	flag  string
+	env   string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+var options = []option{
+	{"host", "SERVER_HOST", "interface to listen on", func(cfg *Config, v string) error {
+		cfg.Server.Host = v
+		return nil
+	}},
+	{"port", "SERVER_PORT", "port to listen on", func(cfg *Config, v string) error {
+		return setInt(&cfg.Server.Port, v)
+	}},
+	{"gin-mode", "GIN_MODE", "gin mode: debug, release or test", func(cfg *Config, v string) error {
+		cfg.Server.GinMode = v
+		return nil
+	}},
+	{"shutdown-delay", "SHUTDOWN_DELAY", "time to keep serving after readiness is withdrawn, e.g. 5s", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Server.ShutdownDelay, v)
+	}},
+	{"shutdown-timeout", "SHUTDOWN_TIMEOUT", "time to drain in-flight requests on shutdown, e.g. 15s", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Server.ShutdownTimeout, v)
+	}},
+	{"request-timeout", "REQUEST_TIMEOUT", "default deadline of a request and its queries, 0 disables it", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Server.RequestTimeout, v)
+	}},
+	{"error-format", "ERROR_FORMAT", "default error body: legacy or problem (RFC 7807)", func(cfg *Config, v string) error {
+		cfg.Server.ErrorFormat = v
+		return nil
+	}},
+	{"problem-type-base", "PROBLEM_TYPE_BASE", "prefix of the problem type URI, followed by the error code", func(cfg *Config, v string) error {
+		cfg.Server.ProblemTypeBase = v
+		return nil
+	}},
+	{"trusted-proxies", "TRUSTED_PROXIES", "comma-separated IPs and CIDRs of the proxies trusted to forward the client IP", func(cfg *Config, v string) error {
+		cfg.Server.TrustedProxies = splitList(v)
+		return nil
+	}},
+	{"db-dsn", "DB_DSN", "PostgreSQL connection string", func(cfg *Config, v string) error {
+		cfg.Database.DSN = v
+		return nil
+	}},
+	{"db-min-conns", "DB_MIN_CONNS", "minimum number of pooled connections", func(cfg *Config, v string) error {
+		return setInt32(&cfg.Database.MinConns, v)
+	}},
+	{"db-max-conns", "DB_MAX_CONNS", "maximum number of pooled connections", func(cfg *Config, v string) error {
+		return setInt32(&cfg.Database.MaxConns, v)
+	}},
+	{"db-connect-timeout", "DB_CONNECT_TIMEOUT", "timeout for establishing a connection, e.g. 5s", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Database.ConnectTimeout, v)
+	}},
+	{"db-durability", "DB_DURABILITY", "expected table storage mode: logged or unlogged", func(cfg *Config, v string) error {
+		cfg.Database.Durability = v
+		return nil
+	}},
+	{"metrics", "METRICS_ENABLED", "expose Prometheus metrics: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.Metrics.Enabled, v)
+	}},
+	{"metrics-path", "METRICS_PATH", "path of the Prometheus metrics endpoint", func(cfg *Config, v string) error {
+		cfg.Metrics.Path = v
+		return nil
+	}},
+	{"log-level", "LOG_LEVEL", "log level: debug, info, warn or error", func(cfg *Config, v string) error {
+		cfg.Log.Level = v
+		return nil
+	}},
+	{"log-format", "LOG_FORMAT", "log format: json or text", func(cfg *Config, v string) error {
+		cfg.Log.Format = v
+		return nil
+	}},
+	{"slow-query-threshold", "SLOW_QUERY_THRESHOLD", "log queries slower than this, e.g. 200ms, 0 disables", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Log.SlowQueryThreshold, v)
+	}},
+	{"trace-exporter", "TRACE_EXPORTER", "trace exporter: none, stdout, file or otlp", func(cfg *Config, v string) error {
+		cfg.Tracing.Exporter = v
+		return nil
+	}},
+	{"trace-file", "TRACE_FILE", "file the file trace exporter appends to", func(cfg *Config, v string) error {
+		cfg.Tracing.File = v
+		return nil
+	}},
+	{"trace-endpoint", "TRACE_ENDPOINT", "host:port of the OTLP/HTTP collector", func(cfg *Config, v string) error {
+		cfg.Tracing.Endpoint = v
+		return nil
+	}},
+	{"trace-sample-ratio", "TRACE_SAMPLE_RATIO", "share of traces to record, 0..1", func(cfg *Config, v string) error {
+		return setFloat(&cfg.Tracing.SampleRatio, v)
+	}},
+	{"default-language", "DEFAULT_LANGUAGE", "language of error messages when Accept-Language matches none, e.g. ru or en", func(cfg *Config, v string) error {
+		cfg.Locale.DefaultLanguage = v
+		return nil
+	}},
+	{"locale-dir", "LOCALE_DIR", "directory with extra <language>.json message catalogs", func(cfg *Config, v string) error {
+		cfg.Locale.Dir = v
+		return nil
+	}},
+	{"clear-enabled", "CLEAR_ENABLED", "enable POST /api/service/clear for admins: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.Admin.ClearEnabled, v)
+	}},
+	{"auth-secret", "AUTH_SECRET", "key signing access tokens, at least 32 bytes, prefer the environment variable", func(cfg *Config, v string) error {
+		cfg.Auth.Secret = v
+		return nil
+	}},
+	{"auth-access-ttl", "AUTH_ACCESS_TTL", "lifetime of access tokens, e.g. 15m", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Auth.AccessTTL, v)
+	}},
+	{"auth-refresh-ttl", "AUTH_REFRESH_TTL", "lifetime of refresh tokens, e.g. 720h", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Auth.RefreshTTL, v)
+	}},
+	{"auth-required", "AUTH_REQUIRED", "reject writes without an access token: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.Auth.Required, v)
+	}},
+	{"auth-anonymous-edits", "AUTH_ANONYMOUS_EDITS", "let anonymous requests edit any profile, thread and post, for legacy clients: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.Auth.AnonymousEdits, v)
+	}},
+	{"rate-limit", "RATE_LIMIT_ENABLED", "throttle clients: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.RateLimit.Enabled, v)
+	}},
+	{"rate-limit-backend", "RATE_LIMIT_BACKEND", "where rate limits are kept: memory or postgres", func(cfg *Config, v string) error {
+		cfg.RateLimit.Backend = v
+		return nil
+	}},
+	{"rate-limit-read-rate", "RATE_LIMIT_READ_RATE", "reads per second a client may make on average", func(cfg *Config, v string) error {
+		return setFloat(&cfg.RateLimit.Read.Rate, v)
+	}},
+	{"rate-limit-read-burst", "RATE_LIMIT_READ_BURST", "reads a client may make at once", func(cfg *Config, v string) error {
+		return setInt(&cfg.RateLimit.Read.Burst, v)
+	}},
+	{"rate-limit-write-rate", "RATE_LIMIT_WRITE_RATE", "writes per second a client may make on average, a batch of posts counts each post", func(cfg *Config, v string) error {
+		return setFloat(&cfg.RateLimit.Write.Rate, v)
+	}},
+	{"rate-limit-write-burst", "RATE_LIMIT_WRITE_BURST", "writes a client may make at once", func(cfg *Config, v string) error {
+		return setInt(&cfg.RateLimit.Write.Burst, v)
+	}},
+	{"rate-limit-vote-rate", "RATE_LIMIT_VOTE_RATE", "votes per second a client may make on average", func(cfg *Config, v string) error {
+		return setFloat(&cfg.RateLimit.Vote.Rate, v)
+	}},
+	{"rate-limit-vote-burst", "RATE_LIMIT_VOTE_BURST", "votes a client may make at once", func(cfg *Config, v string) error {
+		return setInt(&cfg.RateLimit.Vote.Burst, v)
+	}},
+	{"idempotency", "IDEMPOTENCY_ENABLED", "honor Idempotency-Key on create endpoints: true or false", func(cfg *Config, v string) error {
+		return setBool(&cfg.Idempotency.Enabled, v)
+	}},
+	{"idempotency-ttl", "IDEMPOTENCY_TTL", "how long responses are kept for retries with the same Idempotency-Key, e.g. 24h", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Idempotency.TTL, v)
+	}},
+	{"idempotency-lease", "IDEMPOTENCY_LEASE", "how long a running request holds its Idempotency-Key before a retry may take it over, e.g. 1m", func(cfg *Config, v string) error {
+		return setDuration(&cfg.Idempotency.Lease, v)
+	}},
+}
+
+// Load builds the configuration from defaults, the config file, the
+// environment and args (usually os.Args[1:]). Positional arguments left
+// after the flags are returned as rest.
+func Load(args []string) (cfg *Config, rest []string, err error) {
+	fs := flag.NewFlagSet("forum", flag.ContinueOnError)
+	configPath := fs.String("config", os.Getenv(envPrefix+"CONFIG"), "path to a .toml or .yaml config file (env "+envPrefix+"CONFIG)")
+
+	overrides := make(map[string]string)
+	for _, opt := range options {
+		name := opt.flag
+		fs.Func(name, fmt.Sprintf("%s (env %s%s)", opt.usage, envPrefix, opt.env), func(v string) error {
+			overrides[name] = v
+			return nil
+		})
+	}
+
+	if err = fs.Parse(args); err != nil {
+		return nil, nil, err
+	}
+	rest = fs.Args()
+
+	cfg = Default()
+	if *configPath != "" {
+		if err = loadFile(cfg, *configPath); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var problems ValidationError
+	for _, opt := range options {
+		if v, ok := os.LookupEnv(envPrefix + opt.env); ok {
+			if setErr := opt.set(cfg, v); setErr != nil {
+				problems = append(problems, fmt.Sprintf("%s%s: %v", envPrefix, opt.env, setErr))
+			}
+		}
+	}
+	for _, opt := range options {
+		if v, ok := overrides[opt.flag]; ok {
+			if setErr := opt.set(cfg, v); setErr != nil {
+				problems = append(problems, fmt.Sprintf("-%s: %v", opt.flag, setErr))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return nil, nil, problems
+	}
+
+	if err = cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, rest, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.NewDecoder(bytes.NewReader(data)).DisallowUnknownFields().Decode(cfg)
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file format %q, use .toml or .yaml", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("can't parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func setBool(dst *bool, v string) error {
+	parsed, err := strconv.ParseBool(strings.TrimSpace(v))
+	if err != nil {
+		return fmt.Errorf("%q is not a boolean", v)
+	}
+	*dst = parsed
+	return nil
+}
+
+func setFloat(dst *float64, v string) error {
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil {
+		return fmt.Errorf("%q is not a number", v)
+	}
+	*dst = parsed
+	return nil
+}
+
+func setInt(dst *int, v string) error {
+	parsed, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", v)
+	}
+	*dst = parsed
+	return nil
+}
+
+func setInt32(dst *int32, v string) error {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(v), 10, 32)
+	if err != nil {
+		return fmt.Errorf("%q is not an integer", v)
+	}
+	*dst = int32(parsed)
+	return nil
+}
+
+// splitList splits a comma-separated value, dropping empty items.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func setDuration(dst *Duration, v string) error {
+	parsed, err := time.ParseDuration(strings.TrimSpace(v))
+	if err != nil {
+		return fmt.Errorf("%q is not a duration", v)
+	}
+	*dst = Duration(parsed)
+	return nil
+}
@@ -6,6 +6,32 @@ const (
 	PostThread string = "thread"
 )
 
+const (
+	DurabilityLogged   string = "logged"
+	DurabilityUnlogged string = "unlogged"
+	DurabilityMixed    string = "mixed"
+)
+
+// DataTables lists the forum tables, referenced tables before the tables referencing them.
+var DataTables = []string{"users", "forums", "forum_users", "threads", "votes", "posts", "counters", "refresh_tokens", "user_roles", "forum_moderators", "api_keys", "idempotency_keys"}
+
+const (
+	HealthOK          string = "ok"
+	HealthDegraded    string = "degraded"
+	HealthUnavailable string = "unavailable"
+)
+
+// PoolSaturationDegraded is the pool saturation from which the deep health
+// check reports the service as degraded.
+const PoolSaturationDegraded = 0.9
+
+// Triggers lists the triggers maintaining votes, post paths, the row counters
+// and forum users. The service returns wrong data when one of them is missing.
+var Triggers = []string{"insertVote", "updateVote", "newPath", "threadsCounter", "postsCounter", "addUserByForum", "addUserByPosts",
+	"usersInserted", "usersDeleted", "usersTruncated", "forumsInserted", "forumsDeleted", "forumsTruncated",
+	"forumUsersInserted", "forumUsersDeleted", "forumUsersTruncated", "threadsInserted", "threadsDeleted", "threadsTruncated",
+	"votesInserted", "votesDeleted", "votesTruncated", "postsInserted", "postsDeleted", "postsTruncated"}
+
 type SortType string
 
 const (
@@ -14,11 +40,18 @@ const (
 	SortParentTree SortType = "parent_tree"
 )
 
+const (
+	SortTitle   SortType = "title"
+	SortCreated SortType = "created"
+	SortThreads SortType = "threads"
+	SortPosts   SortType = "posts"
+)
+
 var (
 	DescSincePostQuery = map[SortType]string{
-		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 AND id < $2 ORDER BY id DESC LIMIT $3",
-		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 AND path < (SELECT path FROM posts WHERE id = $2) ORDER BY path DESC LIMIT $3",
 		SortParentTree: `
 WITH roots AS (
@@ -30,13 +63,13 @@ WITH roots AS (
     ORDER BY path[1] DESC
     LIMIT $3
 )
-SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message
+SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL
 FROM posts WHERE thread = $1 AND path[1] IN (SELECT * FROM roots) ORDER BY path[1] DESC, path[2:]`,
 	}
 	AscSincePostQuery = map[SortType]string{
-		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 AND id > $2 ORDER BY id LIMIT $3",
-		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 AND path > (SELECT path FROM posts WHERE id = $2) " +
 			"ORDER BY path LIMIT $3",
 		SortParentTree: `
@@ -49,13 +82,13 @@ WITH roots AS (
     ORDER BY path[1]
     LIMIT $3
 )
-SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message
+SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL
 FROM posts WHERE thread = $1 AND path[1] IN (SELECT * FROM roots) ORDER BY path`,
 	}
 	DescNoSincePostQuery = map[SortType]string{
-		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 ORDER BY id DESC LIMIT $2",
-		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 ORDER BY path DESC LIMIT $2",
 		SortParentTree: `
 WITH roots AS (
@@ -65,13 +98,13 @@ WITH roots AS (
     ORDER BY path[1] DESC
     LIMIT $2
 )
-SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message
+SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL
 FROM posts WHERE thread = $1 AND path[1] IN (SELECT * FROM roots) ORDER BY path[1] DESC, path[2:]`,
 	}
 	AscNoSincePostQuery = map[SortType]string{
-		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortFlat: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 ORDER BY id LIMIT $2",
-		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message " +
+		SortTree: "SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL " +
 			"FROM posts WHERE thread = $1 ORDER BY path LIMIT $2\n",
 		SortParentTree: `WITH roots AS (
     SELECT DISTINCT path[1]
@@ -80,7 +113,7 @@ FROM posts WHERE thread = $1 AND path[1] IN (SELECT * FROM roots) ORDER BY path[
     ORDER BY path[1]
     LIMIT $2
 )
-SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message
+SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL
 FROM posts
 WHERE thread = $1 AND path[1] IN (SELECT * FROM roots)
 ORDER BY path`,
@@ -89,13 +122,14 @@ ORDER BY path`,
 
 var (
 	ForumQuery = map[SortType]string{
-		"Create":                `INSERT INTO forums ("user", slug, title) VALUES ((SELECT nickname FROM users WHERE nickname = $3), $1, $2) RETURNING slug, title, "user", posts, threads`,
-		"Get":                   `SELECT id, slug, title, "user", posts, threads FROM forums WHERE slug = $1`,
+		"Create":                `INSERT INTO forums ("user", slug, title, description) VALUES ((SELECT nickname FROM users WHERE nickname = $3), $1, $2, $4) RETURNING slug, title, "user", posts, threads, description, created`,
+		"Get":                   `SELECT id, slug, title, "user", posts, threads, description, created FROM forums WHERE slug = $1`,
 		"GetThreadsDesc":        ` AND created <= $2 ORDER BY created DESC LIMIT $3`,
 		"GetThreadsSinceDesc":   ` ORDER BY created DESC LIMIT $2`,
 		"GetThreadsNoDesc":      ` AND created >= $2 ORDER BY created LIMIT $3`,
 		"GetThreadsSinceNoDesc": ` ORDER BY created LIMIT $2`,
-		"GetThreads":            `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes FROM threads WHERE forum = $1`,
+		"GetThreads":            `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes, archived_at IS NOT NULL FROM threads WHERE forum = $1 AND deleted_at IS NULL`,
+		"GetThreadsActive":      ` AND archived_at IS NULL`,
 		"GetUsers":              `SELECT u.nickname, u.fullname, u.about, u.email FROM forum_users AS fu JOIN users AS u ON fu.nickname = u.nickname WHERE fu.forum = $1 `,
 		"GetUsersDesc":          `ORDER BY u.nickname DESC LIMIT $2`,
 		"GetUsersSinceDesc":     `AND u.nickname < $2 ORDER BY u.nickname DESC LIMIT $3`,
@@ -103,44 +137,239 @@ var (
 		"GetUsersSinceNoDesc":   `AND u.nickname > $2 ORDER BY u.nickname LIMIT $3`,
 		"CreateThread": `INSERT INTO threads (slug, author, forum, title, message, created) VALUES (NULLIF($1, ''), (SELECT nickname FROM users WHERE nickname = $2), 
 		(SELECT slug FROM forums WHERE slug = $3), $4, $5, $6) RETURNING id, $1, author, forum, title, message, created, votes`,
+		// Update renames the forum when $2 is set; the new slug cascades to
+		// the tables referencing it. A NULL $4 keeps the description, '' clears
+		// it. An unknown new owner sets "user" to NULL.
+		"Update": `UPDATE forums SET slug = COALESCE(NULLIF($2::citext, ''), slug), title = COALESCE(NULLIF($3, ''), title), 
+		description = COALESCE($4::text, description), 
+		"user" = CASE WHEN NULLIF($5::citext, '') IS NULL THEN "user" ELSE (SELECT nickname FROM users WHERE nickname = $5::citext) END 
+		WHERE slug = $1 RETURNING id, slug, title, "user", posts, threads, description, created`,
+	}
+	// ForumListQuery pages through the forums ordered by a column of
+	// ForumListColumns and the slug. %[1]s is the column, %[2]s its type.
+	ForumListQuery = map[SortType]string{
+		"List": `SELECT id, slug, title, "user", posts, threads, description, created FROM forums 
+		WHERE ($1 = '' OR title ILIKE $1 OR slug ILIKE $1)`,
+		"After":     ` AND (%[1]s, slug) > ($2::%[2]s, $3::citext)`,
+		"Before":    ` AND (%[1]s, slug) < ($2::%[2]s, $3::citext)`,
+		"Order":     ` ORDER BY %[1]s, slug LIMIT $%[3]d`,
+		"OrderDesc": ` ORDER BY %[1]s DESC, slug DESC LIMIT $%[3]d`,
+	}
+	ForumListColumns = map[SortType][2]string{
+		SortTitle:   {"title", "text"},
+		SortCreated: {"created", "timestamptz"},
+		SortThreads: {"threads", "integer"},
+		SortPosts:   {"posts", "integer"},
 	}
 	PostQuery = map[SortType]string{
-		"Get": `SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message FROM posts WHERE id = $1`,
+		"Get": `SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL FROM posts WHERE id = $1`,
+		// GetVisible hides the posts of deleted threads, like the thread listings.
+		"GetVisible": `SELECT id, COALESCE(parent, 0), author, forum, thread, created, isEdited, CASE WHEN deleted_at IS NULL THEN message ELSE '' END, deleted_at IS NOT NULL FROM posts p 
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.id = p.thread AND t.deleted_at IS NOT NULL)`,
 		"Update": `UPDATE posts SET message = COALESCE(NULLIF($1, ''), message), 
 		isEdited = CASE WHEN (isEdited = TRUE OR (isEdited = FALSE AND NULLIF($1, '') IS NOT NULL AND NULLIF($1, '') <> message)) 
-		THEN TRUE ELSE FALSE END WHERE id = $2 
+		THEN TRUE ELSE FALSE END WHERE id = $2 AND deleted_at IS NULL 
+		AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.id = posts.thread AND (t.archived_at IS NOT NULL OR t.deleted_at IS NOT NULL)) 
 		RETURNING id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message`,
+		// Delete and Restore change nothing when the post already is in the
+		// state asked for, so the forum counter moves once.
+		"Delete": `WITH deleted AS (UPDATE posts SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING forum, thread) 
+		UPDATE forums SET posts = posts - 1 WHERE slug = (SELECT forum FROM deleted) 
+		AND EXISTS (SELECT 1 FROM threads WHERE id = (SELECT thread FROM deleted) AND deleted_at IS NULL)`,
+		"Restore": `WITH restored AS (UPDATE posts SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING forum, thread) 
+		UPDATE forums SET posts = posts + 1 WHERE slug = (SELECT forum FROM restored) 
+		AND EXISTS (SELECT 1 FROM threads WHERE id = (SELECT thread FROM restored) AND deleted_at IS NULL)`,
+		// PurgeLock keeps replies from being added, as newPath locks the parent
+		// too; PurgeReplies must run after it to see the replies committed meanwhile.
+		"PurgeLock": `SELECT forum::text, author::text, 
+		deleted_at IS NULL AND EXISTS (SELECT 1 FROM threads t WHERE t.id = p.thread AND t.deleted_at IS NULL) 
+		FROM posts p WHERE id = $1 FOR UPDATE OF p`,
+		"PurgeReplies": `SELECT EXISTS (SELECT 1 FROM posts WHERE parent = $1)`,
+		"Purge":        `DELETE FROM posts WHERE id = $1`,
+		"PurgeCounter": `UPDATE forums SET posts = posts - 1 WHERE slug = $1`,
 	}
 	ServiceQuery = map[SortType]string{
-		"Clear":        `TRUNCATE users, forums, threads, votes, posts, forum_users`,
-		"queryUsers":   `SELECT COUNT(*) FROM users`,
-		"queryForums":  `SELECT COUNT(*) FROM forums`,
-		"queryThreads": `SELECT COUNT(*) FROM threads`,
-		"queryPosts":   `SELECT COUNT(*) FROM posts`,
+		// Clear, ClearRefreshTokens and ClearUsers keep the users with a role
+		// and their sessions, so admins can still log in after a clear.
+		"Clear":              `TRUNCATE forums, threads, votes, posts, forum_users, forum_moderators, api_keys, idempotency_keys`,
+		"ClearRefreshTokens": `DELETE FROM refresh_tokens WHERE nickname NOT IN (SELECT nickname FROM user_roles)`,
+		"ClearUsers":         `DELETE FROM users WHERE nickname NOT IN (SELECT nickname FROM user_roles)`,
+		"KeptUsers":          `SELECT COUNT(DISTINCT nickname) FROM user_roles`,
+		"queryUsers":         `SELECT COUNT(*) FROM users`,
+		"queryForums":        `SELECT COUNT(*) FROM forums`,
+		"queryThreads":       `SELECT COUNT(*) FROM threads`,
+		"queryPosts":         `SELECT COUNT(*) FROM posts`,
+		"queryVotes":         `SELECT COUNT(*) FROM votes`,
+		"queryForumUsers":    `SELECT COUNT(*) FROM forum_users`,
+		"Counters":           `SELECT name, SUM(value)::bigint FROM counters GROUP BY name`,
+		// CompactCounters folds the rows appended by the counter triggers into one per table.
+		"CompactCounters": `WITH deleted AS (DELETE FROM counters RETURNING name, value) 
+		INSERT INTO counters (name, value) SELECT name, SUM(value) FROM deleted GROUP BY name`,
+		"LockForum": `SELECT slug FROM forums WHERE slug = $1 FOR UPDATE`,
+		// CountForum and CountThread count what ClearForum and ClearThread delete, for dry runs.
+		"CountForum": `SELECT (SELECT COUNT(*) FROM votes WHERE thread IN (SELECT id FROM threads WHERE forum = f.slug)), 
+		(SELECT COUNT(*) FROM posts WHERE forum = f.slug), 
+		(SELECT COUNT(*) FROM threads WHERE forum = f.slug), 
+		(SELECT COUNT(*) FROM forum_users WHERE forum = f.slug) 
+		FROM forums f WHERE f.slug = $1`,
+		"CountThread": `SELECT (SELECT COUNT(*) FROM votes WHERE thread = t.id), 
+		(SELECT COUNT(*) FROM posts WHERE thread = t.id), 
+		(SELECT COUNT(*) FROM forum_users fu WHERE fu.forum = t.forum 
+		AND (fu.nickname = t.author OR EXISTS (SELECT 1 FROM posts p WHERE p.thread = t.id AND p.author = fu.nickname)) 
+		AND NOT EXISTS (SELECT 1 FROM threads o WHERE o.forum = fu.forum AND o.author = fu.nickname AND o.id <> t.id) 
+		AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.forum = fu.forum AND p.author = fu.nickname AND p.thread <> t.id)) 
+		FROM threads t WHERE t.id = $1`,
+		"ClearForumVotes": `WITH deleted AS (DELETE FROM votes WHERE thread IN (SELECT id FROM threads WHERE forum = $1) RETURNING 1) 
+		SELECT COUNT(*) FROM deleted`,
+		"ClearForumPosts":      `WITH deleted AS (DELETE FROM posts WHERE forum = $1 RETURNING 1) SELECT COUNT(*) FROM deleted`,
+		"ClearForumThreads":    `WITH deleted AS (DELETE FROM threads WHERE forum = $1 RETURNING 1) SELECT COUNT(*) FROM deleted`,
+		"ClearForumUsers":      `WITH deleted AS (DELETE FROM forum_users WHERE forum = $1 RETURNING 1) SELECT COUNT(*) FROM deleted`,
+		"ClearForumModerators": `DELETE FROM forum_moderators WHERE forum = $1`,
+		"ClearForumAPIKeys":    `UPDATE api_keys SET forum = NULL, revoked_at = COALESCE(revoked_at, now()) WHERE forum = $1`,
+		"ClearForum":           `DELETE FROM forums WHERE slug = $1`,
+		"LockThread":           `SELECT forum, deleted_at IS NULL FROM threads WHERE id = $1 FOR UPDATE`,
+		"ClearThreadVotes":     `WITH deleted AS (DELETE FROM votes WHERE thread = $1 RETURNING 1) SELECT COUNT(*) FROM deleted`,
+		"ClearThreadPosts": `WITH deleted AS (DELETE FROM posts WHERE thread = $1 RETURNING author, deleted_at IS NULL AS counted) 
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE counted), COALESCE(array_agg(DISTINCT author::text), '{}') FROM deleted`,
+		"ClearThread":         `DELETE FROM threads WHERE id = $1 RETURNING author::text`,
+		"ClearThreadCounters": `UPDATE forums SET threads = threads - $2, posts = posts - $3 WHERE slug = $1`,
+		"ClearStaleForumUsers": `WITH deleted AS (DELETE FROM forum_users fu WHERE fu.forum = $1 AND fu.nickname = ANY($2::text[]::citext[]) 
+		AND NOT EXISTS (SELECT 1 FROM threads t WHERE t.forum = fu.forum AND t.author = fu.nickname) 
+		AND NOT EXISTS (SELECT 1 FROM posts p WHERE p.forum = fu.forum AND p.author = fu.nickname) RETURNING 1) 
+		SELECT COUNT(*) FROM deleted`,
+		"Durability": `SELECT relname, relpersistence = 'p' FROM pg_class 
+		WHERE relkind = 'r' AND relnamespace = 'public'::regnamespace AND relname::text = ANY($1::text[])`,
+		"SetLogged":   `ALTER TABLE %s SET LOGGED`,
+		"SetUnlogged": `ALTER TABLE %s SET UNLOGGED`,
+		"Ping":        `SELECT 1`,
+		"Triggers": `SELECT t.tgname::text FROM pg_trigger t JOIN pg_class c ON c.oid = t.tgrelid 
+		WHERE NOT t.tgisinternal AND c.relnamespace = 'public'::regnamespace`,
 	}
 	ThreadQuery = map[SortType]string{
-		"GetBySlug":        `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes FROM threads WHERE slug = $1`,
-		"PostsCreate":      `INSERT INTO posts(parent, author, forum, thread, message, created) VALUES `,
-		"CreatePostsTwo":   ` RETURNING id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message`,
-		"VoteByID":         `INSERT INTO votes (nickname, thread, value) VALUES ($1, $2, $3) ON CONFLICT (nickname, thread) DO UPDATE SET value = $3`,
+		"GetBySlug":      `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes, archived_at IS NOT NULL FROM threads WHERE slug = $1 AND deleted_at IS NULL`,
+		"PostsCreate":    `INSERT INTO posts(parent, author, forum, thread, message, created) VALUES `,
+		"CreatePostsTwo": ` RETURNING id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message`,
+		"VoteByID": `INSERT INTO votes (nickname, thread, value) SELECT $1, id, $3 FROM threads WHERE id = $2 AND deleted_at IS NULL AND archived_at IS NULL 
+		ON CONFLICT (nickname, thread) DO UPDATE SET value = $3`,
 		"CreatePostsBatch": `INSERT INTO posts(parent, author, forum, thread, message, created) VALUES (NULLIF($1, 0), $2, $3, $4, $5, $6) RETURNING id, COALESCE(parent, 0), author, forum, thread, created, isEdited, message`,
-		"VoteBySlug": `INSERT INTO votes (nickname, thread, value) VALUES ($1, (SELECT id FROM threads WHERE slug=$2), $3) 
+		"VoteBySlug": `INSERT INTO votes (nickname, thread, value) SELECT $1, id, $3 FROM threads WHERE slug = $2 AND deleted_at IS NULL AND archived_at IS NULL 
 		ON CONFLICT (nickname, thread) DO UPDATE SET value = $3`,
 		"UpdateByID": `UPDATE threads SET title = COALESCE(NULLIF($1, ''), title), 
-		message = COALESCE(NULLIF($2, ''), message) WHERE id = $3 
+		message = COALESCE(NULLIF($2, ''), message) WHERE id = $3 AND deleted_at IS NULL AND archived_at IS NULL 
 		RETURNING id, COALESCE(slug, ''), author, forum, title, message, created, votes`,
-		"GetByID": `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes FROM threads WHERE id = $1`,
+		"GetByID": `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes, archived_at IS NOT NULL FROM threads WHERE id = $1 AND deleted_at IS NULL`,
 		"UpdateBySlug": `UPDATE threads SET title = COALESCE(NULLIF($1, ''), title), 
-		message = COALESCE(NULLIF($2, ''), message) WHERE slug = $3 
+		message = COALESCE(NULLIF($2, ''), message) WHERE slug = $3 AND deleted_at IS NULL AND archived_at IS NULL 
 		RETURNING id, slug, author, forum, title, message, created, votes`,
+		"GetDeleted": `SELECT id, COALESCE(slug, ''), author, forum, title, message, created, votes, archived_at IS NOT NULL FROM threads 
+		WHERE (id = $1 OR slug = NULLIF($2, '')) AND deleted_at IS NOT NULL`,
+		// Delete and Restore take the thread and its remaining posts out of
+		// the forum counters and put them back.
+		"Delete": `WITH deleted AS (UPDATE threads SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING forum) 
+		UPDATE forums SET threads = threads - 1, posts = posts - (SELECT COUNT(*) FROM posts WHERE thread = $1 AND deleted_at IS NULL) 
+		WHERE slug = (SELECT forum FROM deleted)`,
+		"Restore": `WITH restored AS (UPDATE threads SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING forum) 
+		UPDATE forums SET threads = threads + 1, posts = posts + (SELECT COUNT(*) FROM posts WHERE thread = $1 AND deleted_at IS NULL) 
+		WHERE slug = (SELECT forum FROM restored)`,
+		"Archive": `UPDATE threads SET archived_at = CASE WHEN $2 THEN COALESCE(archived_at, now()) END WHERE id = $1 AND deleted_at IS NULL 
+		RETURNING id, COALESCE(slug, ''), author, forum, title, message, created, votes, archived_at IS NOT NULL`,
 	}
 	UserQuery = map[SortType]string{
 		"Get":               `SELECT nickname, fullname, about, email FROM users WHERE nickname = $1`,
-		"Create":            `INSERT INTO users (nickname, fullname, about, email) VALUES ($1, $2, $3, $4)`,
+		"Create":            `INSERT INTO users (nickname, fullname, about, email, password_hash) VALUES ($1, $2, $3, $4, NULLIF($5, ''))`,
 		"GetUsersByUserNOE": `SELECT nickname, fullname, about, email FROM users WHERE nickname = $1 OR email = $2`,
 		"Update": `UPDATE users SET fullname = COALESCE(NULLIF($1, ''), fullname), 
 		about = COALESCE(NULLIF($2, ''), about), 
-		email = COALESCE(NULLIF($3, ''), email) WHERE nickname = $4 
+		email = COALESCE(NULLIF($3, ''), email), 
+		password_hash = COALESCE(NULLIF($5, ''), password_hash) WHERE nickname = $4 
 		RETURNING nickname, fullname, about, email`,
+		// All is completed by the filters below, %d being the argument number.
+		// All leaves the emails out, the directory is public.
+		"All":          `SELECT nickname, fullname, about FROM users WHERE TRUE`,
+		"AllSince":     ` AND nickname > $%d`,
+		"AllSinceDesc": ` AND nickname < $%d`,
+		"AllPrefix":    ` AND (nickname::text ILIKE $%[1]d OR fullname ILIKE $%[1]d OR fullname ILIKE '%% ' || $%[1]d)`,
+		"AllFuzzy":     ` AND (nickname::text %% $%[1]d OR $%[1]d <%% fullname)`,
+		"AllActiveSince": ` AND (EXISTS (SELECT 1 FROM threads t WHERE t.author = users.nickname AND t.created >= $%[1]d) 
+		OR EXISTS (SELECT 1 FROM posts p WHERE p.author = users.nickname AND p.created >= $%[1]d))`,
+		"AllOrder":     ` ORDER BY nickname`,
+		"AllOrderDesc": ` ORDER BY nickname DESC`,
+		// AllOrderFuzzy puts the closest matches first.
+		"AllOrderFuzzy": ` ORDER BY GREATEST(similarity(nickname::text, $%[1]d), word_similarity($%[1]d, fullname)) DESC, nickname`,
+		"AllLimit":      ` LIMIT $%d`,
+	}
+	AuthQuery = map[SortType]string{
+		"PasswordHash":  `SELECT nickname, COALESCE(password_hash, '') FROM users WHERE nickname = $1`,
+		"SetPassword":   `UPDATE users SET password_hash = $2 WHERE nickname = $1 RETURNING nickname`,
+		"CreateRefresh": `INSERT INTO refresh_tokens (token_hash, nickname, expires_at) VALUES ($1, $2, $3)`,
+		"UseRefresh": `UPDATE refresh_tokens SET revoked_at = now() 
+		WHERE token_hash = $1 AND revoked_at IS NULL AND expires_at > now() RETURNING nickname`,
+		"RevokeRefresh": `UPDATE refresh_tokens SET revoked_at = now() WHERE token_hash = $1 AND revoked_at IS NULL`,
+	}
+	RoleQuery = map[SortType]string{
+		"Role": `SELECT CASE 
+		WHEN EXISTS (SELECT 1 FROM user_roles WHERE nickname = $1 AND role = 'admin') THEN 'admin' 
+		WHEN EXISTS (SELECT 1 FROM forums WHERE slug = $2 AND "user" = $1) THEN 'owner' 
+		WHEN EXISTS (SELECT 1 FROM forum_moderators WHERE forum = $2 AND nickname = $1) THEN 'moderator' 
+		ELSE 'member' END`,
+		"Moderators": `SELECT forum, nickname, COALESCE(granted_by, ''), created FROM forum_moderators WHERE forum = $1 ORDER BY nickname`,
+		"GrantModerator": `INSERT INTO forum_moderators (forum, nickname, granted_by) 
+		VALUES ($1, (SELECT nickname FROM users WHERE nickname = $2), NULLIF($3, '')) 
+		ON CONFLICT (forum, nickname) DO UPDATE SET nickname = EXCLUDED.nickname 
+		RETURNING forum, nickname, COALESCE(granted_by, ''), created`,
+		"RevokeModerator": `DELETE FROM forum_moderators WHERE forum = $1 AND nickname = $2`,
+		"Admins":          `SELECT nickname FROM user_roles WHERE role = 'admin' ORDER BY nickname`,
+		"GrantAdmin": `INSERT INTO user_roles (nickname, role) VALUES ((SELECT nickname FROM users WHERE nickname = $1), 'admin') 
+		ON CONFLICT DO NOTHING`,
+		"RevokeAdmin": `DELETE FROM user_roles WHERE nickname = $1 AND role = 'admin'`,
+	}
+	APIKeyQuery = map[SortType]string{
+		"Create": `INSERT INTO api_keys (nickname, name, prefix, key_hash, scopes, forum) 
+		VALUES ((SELECT nickname FROM users WHERE nickname = $1), $2, $3, $4, $5, NULLIF($6, '')) 
+		RETURNING id, name, prefix, scopes, COALESCE(forum, ''), created, last_used`,
+		"List": `SELECT id, name, prefix, scopes, COALESCE(forum, ''), created, last_used FROM api_keys 
+		WHERE nickname = $1 AND revoked_at IS NULL ORDER BY id`,
+		"Revoke": `UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND nickname = $2 AND revoked_at IS NULL`,
+		"GetByHash": `SELECT id, nickname, scopes, COALESCE(forum, ''), last_used FROM api_keys 
+		WHERE key_hash = $1 AND revoked_at IS NULL`,
+		"Touch": `UPDATE api_keys SET last_used = now() WHERE id = $1`,
+	}
+	RateLimitQuery = map[SortType]string{
+		// $1 key, $2 burst, $3 cost, $4 rate per second. New buckets start full.
+		"Take": `INSERT INTO rate_limits AS r (key, tokens, allowed, updated) 
+		VALUES ($1, $2::float8 - $3::float8, true, clock_timestamp()) 
+		ON CONFLICT (key) DO UPDATE SET 
+		tokens = CASE WHEN LEAST($2::float8, r.tokens + $4::float8 * EXTRACT(EPOCH FROM clock_timestamp() - r.updated)::float8) >= $3::float8 
+			THEN LEAST($2::float8, r.tokens + $4::float8 * EXTRACT(EPOCH FROM clock_timestamp() - r.updated)::float8) - $3::float8 
+			ELSE LEAST($2::float8, r.tokens + $4::float8 * EXTRACT(EPOCH FROM clock_timestamp() - r.updated)::float8) END, 
+		allowed = LEAST($2::float8, r.tokens + $4::float8 * EXTRACT(EPOCH FROM clock_timestamp() - r.updated)::float8) >= $3::float8, 
+		updated = clock_timestamp() 
+		RETURNING tokens, allowed`,
+		"Forget": `DELETE FROM rate_limits WHERE updated < $1`,
+	}
+	IdempotencyQuery = map[SortType]string{
+		// Claims the key for $5 seconds unless a request newer than $4
+		// holds it, finished or within its lease, and returns what that request stored.
+		"Claim": `WITH claimed AS (INSERT INTO idempotency_keys AS i (scope, key, fingerprint, created, lease_until) 
+		VALUES ($1, $2, $3, now(), now() + $5 * INTERVAL '1 second') 
+		ON CONFLICT (scope, key) DO UPDATE SET fingerprint = EXCLUDED.fingerprint, status = NULL, content_type = NULL, body = NULL, 
+		created = EXCLUDED.created, lease_until = EXCLUDED.lease_until 
+		WHERE i.created < $4 OR (i.status IS NULL AND i.lease_until < now()) RETURNING 1) 
+		SELECT EXISTS (SELECT 1 FROM claimed), COALESCE(i.fingerprint, ''), COALESCE(i.status, 0), COALESCE(i.content_type, ''), i.body 
+		FROM (SELECT 1) AS one LEFT JOIN idempotency_keys i ON i.scope = $1 AND i.key = $2`,
+		"Complete": `UPDATE idempotency_keys SET status = $3, content_type = $4, body = $5 WHERE scope = $1 AND key = $2`,
+		"Release":  `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND status IS NULL`,
+		"Forget":   `DELETE FROM idempotency_keys WHERE created < $1`,
+	}
+	MigrationQuery = map[SortType]string{
+		"Lock":         `SELECT pg_advisory_lock($1)`,
+		"Unlock":       `SELECT pg_advisory_unlock($1)`,
+		"TableExists":  `SELECT to_regclass('schema_migrations') IS NOT NULL`,
+		"LegacySchema": `SELECT to_regclass('posts') IS NOT NULL`,
+		"CreateTable": `CREATE TABLE schema_migrations (version INTEGER NOT NULL PRIMARY KEY, name TEXT NOT NULL, 
+		checksum TEXT NOT NULL, applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP)`,
+		"Applied": `SELECT version, name, checksum, applied_at FROM schema_migrations ORDER BY version`,
+		"Insert":  `INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		"Delete":  `DELETE FROM schema_migrations WHERE version = $1`,
 	}
 )
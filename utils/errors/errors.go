@@ -1,51 +1,200 @@
+// Package errors defines the errors the API answers with. An *Error is never
+// modified once created: WithDetails and Wrap return copies, so the values
+// declared here can be shared by concurrent requests.
 package errors
 
 import (
-	"db_project/app/models"
+	"context"
+	"errors"
+	"github.com/jackc/pgconn"
 	"net/http"
 )
 
-type MsgErrors interface {
-	Error() string
-	SetTextDetails(text string) *models.Message
-	Code() int
-}
-
 var (
 	Err23503 = "23503"
 	Err23502 = "23502"
 	Err23505 = "23505"
 	P0001    = "P0001"
+	Err57014 = "57014"
+)
+
+// Error is an API error: the HTTP status, a stable machine-readable key,
+// a human-readable message, optional details and the error that caused it.
+// The key and the details double as keys of the message catalog (utils/i18n),
+// the message is used for languages the catalog has no text for.
+type Error struct {
+	status  int
+	key     string
+	message string
+	details string
+	fields  []FieldError
+	cause   error
+}
+
+// FieldError is a request field that broke a validation rule, e.g.
+// {Field: "limit", Rule: "max", Param: "10000"}.
+type FieldError struct {
+	Field string
+	Rule  string
+	Param string
+}
+
+func New(status int, key string, message string) *Error {
+	return &Error{status: status, key: key, message: message}
+}
+
+func (e *Error) Code() int {
+	return e.status
+}
+
+func (e *Error) Key() string {
+	return e.key
+}
+
+func (e *Error) Message() string {
+	return e.message
+}
+
+func (e *Error) Details() string {
+	return e.details
+}
+
+// WithDetails returns a copy of e carrying details, usually a details key of
+// the message catalog such as "invalid_slug". Details without a catalog
+// entry are shown as they are.
+func (e *Error) WithDetails(details string) *Error {
+	copied := *e
+	copied.details = details
+	return &copied
+}
+
+// WithFields returns a copy of e listing the invalid request fields.
+func (e *Error) WithFields(fields []FieldError) *Error {
+	copied := *e
+	copied.fields = fields
+	return &copied
+}
+
+func (e *Error) Fields() []FieldError {
+	return e.fields
+}
+
+// Wrap returns a copy of e caused by cause. The cause is not shown to clients.
+func (e *Error) Wrap(cause error) *Error {
+	copied := *e
+	copied.cause = cause
+	return &copied
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same key, so that
+// errors.Is(err, NotFoundUser) holds for copies made by WithDetails and Wrap.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.key == e.key
+}
+
+func (e *Error) Error() string {
+	text := e.message
+	if e.details != "" {
+		text += ": " + e.details
+	}
+	for _, field := range e.fields {
+		text += "; " + field.Field + ": " + field.Rule
+	}
+	if e.cause != nil {
+		text += ": " + e.cause.Error()
+	}
+	return text
+}
+
+// Is and As are errors.Is and errors.As, so that callers importing this
+// package as errors don't need the standard one.
+func Is(err error, target error) bool {
+	return errors.Is(err, target)
+}
+
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
+
+var (
+	ServerInternal = New(http.StatusInternalServerError, "internal", "internal server error")
+	BadRequest     = New(http.StatusBadRequest, "bad_request", "bad request")
+	// ValidationFailed lists every invalid field of the request.
+	ValidationFailed = New(http.StatusBadRequest, "validation_failed", "некорректные данные запроса")
+	RequestTimeout   = New(http.StatusGatewayTimeout, "request_timeout", "превышено время ожидания запроса")
+	RequestAborted   = New(http.StatusServiceUnavailable, "request_aborted", "запрос был отменён")
+	RateLimited      = New(http.StatusTooManyRequests, "rate_limited", "слишком много запросов, повторите позже")
+	// IdempotencyMismatch is a key reused for another request.
+	IdempotencyMismatch   = New(http.StatusUnprocessableEntity, "idempotency_key_mismatch", "ключ идемпотентности уже использован для другого запроса")
+	IdempotencyInProgress = New(http.StatusConflict, "idempotency_in_progress", "запрос с этим ключом идемпотентности ещё выполняется")
 )
 
 var (
-	ServerInternal MsgErrors = &models.Message{ErrorCode: http.StatusInternalServerError, Msg: "internal server error"}
-	BadRequest     MsgErrors = &models.Message{ErrorCode: http.StatusBadRequest, Msg: "bad request"}
+	ConflictUserCreate = New(http.StatusConflict, "user_conflict", "пользователь c таким email или nickname уже существует")
+	NotFoundUser       = New(http.StatusNotFound, "user_not_found", "не найден юзер")
+	ConflictUserUpdate = New(http.StatusConflict, "user_update_conflict", "новые данные профиля пользователя конфликтуют с имеющимися пользователями")
+	NotFoundUserUpdate = New(http.StatusNotFound, "user_update_not_found", "не найден пользователь для обновления")
 )
 
 var (
-	ConflictUserCreate MsgErrors = &models.Message{ErrorCode: http.StatusConflict, Msg: "пользователь c таким email или nickname уже существует"}
-	NotFoundUser       MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "не найден юзер"}
-	ConflictUserUpdate MsgErrors = &models.Message{ErrorCode: http.StatusConflict, Msg: "новые данные профиля пользователя конфликтуют с имеющимися пользователями"}
-	NotFoundUserUpdate MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "не найден пользователь для обновления"}
+	ThreadAlreadyExists        = New(http.StatusConflict, "thread_conflict", "тред уже присутсвует в базе данных")
+	ThreadUpdateNotFound       = New(http.StatusNotFound, "thread_update_not_found", "не найден тред для обновления")
+	ThreadUserOrThreadNotFound = New(http.StatusNotFound, "vote_user_or_thread_not_found", "не найден пользователь или тред для голосования")
+	ThreadUserOrForumNotFound  = New(http.StatusNotFound, "thread_user_or_forum_not_found", "автор треда или форуи не найдены")
+	ThreadNotFound             = New(http.StatusNotFound, "thread_not_found", "тред не найден")
+	ThreadArchived             = New(http.StatusConflict, "thread_archived", "тред в архиве и доступен только для чтения")
 )
 
 var (
-	ThreadAlreadyExists        MsgErrors = &models.Message{ErrorCode: http.StatusConflict, Msg: "тред уже присутсвует в базе данных"}
-	ThreadUpdateNotFound       MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "не найден тред для обновления"}
-	ThreadUserOrThreadNotFound MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "не найден пользователь или тред для голосования"}
-	ThreadUserOrForumNotFound  MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "автор треда или форуи не найдены"}
-	ThreadNotFound             MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "тред не найден"}
+	NotFoundForumUser  = New(http.StatusNotFound, "forum_user_not_found", "владелец форума не найден")
+	ForumAlreadyExists = New(http.StatusConflict, "forum_conflict", "форум уже присутсвует в базе данных")
+	NotFoundForum      = New(http.StatusNotFound, "forum_not_found", "форум не найден")
+	ForumSlugConflict  = New(http.StatusConflict, "forum_slug_conflict", "форум с таким slug уже существует")
 )
 
 var (
-	NotFoundForumUser  MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "владелец форума не найден"}
-	ForumAlreadyExists MsgErrors = &models.Message{ErrorCode: http.StatusConflict, Msg: "форум уже присутсвует в базе данных"}
-	NotFoundForum      MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "форум не найден"}
+	PostWrongParent  = New(http.StatusConflict, "post_wrong_parent", "не найден указанный родетель в данном треде")
+	PostUserNotFound = New(http.StatusNotFound, "post_user_not_found", "автор поста не найден")
+	PostNotFound     = New(http.StatusNotFound, "post_not_found", "не найден пост для обновления")
+	PostDeleted      = New(http.StatusConflict, "post_deleted", "пост удалён")
+	PostHasReplies   = New(http.StatusConflict, "post_has_replies", "на пост есть ответы, его можно только скрыть")
 )
 
 var (
-	PostWrongParent  MsgErrors = &models.Message{ErrorCode: http.StatusConflict, Msg: "не найден указанный родетель в данном треде"}
-	PostUserNotFound MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "автор поста не найден"}
-	PostNotFound     MsgErrors = &models.Message{ErrorCode: http.StatusNotFound, Msg: "не найден пост для обновления"}
+	ClearDisabled = New(http.StatusForbidden, "clear_disabled", "очистка базы данных отключена")
 )
+
+var (
+	InvalidCredentials = New(http.StatusUnauthorized, "invalid_credentials", "неверный nickname или пароль")
+	WrongPassword      = New(http.StatusForbidden, "wrong_password", "неверный текущий пароль")
+	InvalidToken       = New(http.StatusUnauthorized, "invalid_token", "токен недействителен или истёк")
+	AuthRequired       = New(http.StatusUnauthorized, "auth_required", "требуется аутентификация")
+	AuthorMismatch     = New(http.StatusForbidden, "author_mismatch", "нельзя действовать от имени другого пользователя")
+	Forbidden          = New(http.StatusForbidden, "forbidden", "недостаточно прав")
+	ModeratorNotFound  = New(http.StatusNotFound, "moderator_not_found", "пользователь не модератор этого форума")
+	APIKeyReadOnly     = New(http.StatusForbidden, "api_key_read_only", "ключ API даёт доступ только на чтение")
+	APIKeyForum        = New(http.StatusForbidden, "api_key_forum", "ключ API не даёт доступа к этому форуму")
+	APIKeyNotFound     = New(http.StatusNotFound, "api_key_not_found", "ключ API не найден")
+)
+
+// Internal maps a repository error that has no specific meaning for the caller.
+// Cancelled requests and exceeded deadlines are reported as 503 and 504,
+// everything else as a server error. The result wraps err.
+func Internal(err error) *Error {
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return RequestTimeout.Wrap(err)
+	case errors.Is(err, context.Canceled):
+		return RequestAborted.Wrap(err)
+	case errors.As(err, &pgErr) && pgErr.SQLState() == Err57014:
+		return RequestTimeout.Wrap(err)
+	default:
+		return ServerInternal.Wrap(err)
+	}
+}
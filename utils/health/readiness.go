@@ -0,0 +1,25 @@
+package health
+
+import "sync/atomic"
+
+// Readiness tells load balancers whether the instance should receive traffic.
+// It starts as not ready and is flipped back to not ready when shutdown begins.
+type Readiness struct {
+	ready int32
+}
+
+func CreateReadiness() *Readiness {
+	return &Readiness{}
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	var value int32
+	if ready {
+		value = 1
+	}
+	atomic.StoreInt32(&r.ready, value)
+}
+
+func (r *Readiness) Ready() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
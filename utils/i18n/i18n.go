@@ -0,0 +1,152 @@
+// Package i18n translates API error messages. Russian and English catalogs
+// are built in; more languages are added by dropping <language>.json files
+// shaped like locales/en.json into a directory given at startup. A file for
+// a built-in language overrides its messages one by one.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"golang.org/x/text/language"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+type messages struct {
+	// Errors is keyed by error code, e.g. "thread_not_found".
+	Errors map[string]string `json:"errors"`
+	// Details is keyed by details key, e.g. "invalid_slug".
+	Details map[string]string `json:"details"`
+	// Fields is keyed by validation rule, e.g. "max"; {param} is replaced
+	// with the rule parameter.
+	Fields map[string]string `json:"fields"`
+}
+
+type Catalog struct {
+	tags     []language.Tag
+	messages []*messages
+	matcher  language.Matcher
+}
+
+// Load reads the built-in catalogs and the *.json files of dir, if dir is
+// not empty. defaultLanguage is used when Accept-Language matches nothing.
+func Load(dir string, defaultLanguage string) (catalog *Catalog, err error) {
+	byLanguage := make(map[language.Tag]*messages)
+
+	if err = loadDir(locales, "locales", byLanguage); err != nil {
+		return
+	}
+	if dir != "" {
+		if err = loadDir(os.DirFS(dir), ".", byLanguage); err != nil {
+			return
+		}
+	}
+
+	defaultTag, err := language.Parse(defaultLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("default language %q: %w", defaultLanguage, err)
+	}
+	if _, ok := byLanguage[defaultTag]; !ok {
+		return nil, fmt.Errorf("no messages for the default language %q", defaultLanguage)
+	}
+
+	// The matcher falls back to its first tag, so the default goes first.
+	catalog = &Catalog{tags: []language.Tag{defaultTag}, messages: []*messages{byLanguage[defaultTag]}}
+	for tag, msgs := range byLanguage {
+		if tag != defaultTag {
+			catalog.tags = append(catalog.tags, tag)
+			catalog.messages = append(catalog.messages, msgs)
+		}
+	}
+	catalog.matcher = language.NewMatcher(catalog.tags)
+	return
+}
+
+func loadDir(fsys fs.FS, dir string, byLanguage map[language.Tag]*messages) error {
+	paths, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range paths {
+		tag, err := language.Parse(strings.TrimSuffix(path.Base(file), ".json"))
+		if err != nil {
+			return fmt.Errorf("%s: file name is not a language tag: %w", file, err)
+		}
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return err
+		}
+		loaded := &messages{}
+		if err = json.Unmarshal(data, loaded); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		msgs, ok := byLanguage[tag]
+		if !ok {
+			msgs = &messages{Errors: make(map[string]string), Details: make(map[string]string), Fields: make(map[string]string)}
+			byLanguage[tag] = msgs
+		}
+		for key, text := range loaded.Errors {
+			msgs.Errors[key] = text
+		}
+		for key, text := range loaded.Details {
+			msgs.Details[key] = text
+		}
+		for rule, text := range loaded.Fields {
+			msgs.Fields[rule] = text
+		}
+	}
+	return nil
+}
+
+// Localizer translates into the language picked from an Accept-Language header.
+type Localizer struct {
+	tag      language.Tag
+	messages *messages
+	fallback *messages
+}
+
+func (c *Catalog) Localizer(acceptLanguage string) *Localizer {
+	_, index := language.MatchStrings(c.matcher, acceptLanguage)
+	return &Localizer{tag: c.tags[index], messages: c.messages[index], fallback: c.messages[0]}
+}
+
+// Language is the tag of the chosen language, for the Content-Language header.
+func (l *Localizer) Language() string {
+	return l.tag.String()
+}
+
+// Error returns the message of an error code, or ok = false if neither the
+// chosen nor the default language has one.
+func (l *Localizer) Error(code string) (text string, ok bool) {
+	if text, ok = l.messages.Errors[code]; !ok {
+		text, ok = l.fallback.Errors[code]
+	}
+	return
+}
+
+// Details works like Error for details keys.
+func (l *Localizer) Details(key string) (text string, ok bool) {
+	if text, ok = l.messages.Details[key]; !ok {
+		text, ok = l.fallback.Details[key]
+	}
+	return
+}
+
+// Field describes a broken validation rule, or returns the rule itself if
+// no language has a text for it.
+func (l *Localizer) Field(rule string, param string) string {
+	text, ok := l.messages.Fields[rule]
+	if !ok {
+		if text, ok = l.fallback.Fields[rule]; !ok {
+			return rule
+		}
+	}
+	return strings.ReplaceAll(text, "{param}", param)
+}
@@ -0,0 +1,61 @@
+// Package idempotency describes how responses to requests sent with an
+// Idempotency-Key header are kept, so a retry of a request gets the response
+// of the first attempt instead of running again.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+const Header = "Idempotency-Key"
+
+// MaxKeyLength bounds the keys clients may send. UUIDs and ULIDs fit easily.
+const MaxKeyLength = 255
+
+// Response is a request seen under a key and, once it has finished, its
+// response. Status is 0 while the request runs.
+type Response struct {
+	Fingerprint string
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// Store keeps the responses, keyed by client (scope) and key.
+type Store interface {
+	// Claim takes key for a request with fingerprint for lease unless a
+	// request claimed it after expired and either finished or still holds its
+	// lease. Otherwise it returns that request, or nil if it claimed the key
+	// concurrently and isn't visible yet.
+	Claim(ctx context.Context, scope string, key string, fingerprint string, expired time.Time, lease time.Duration) (claimed bool, stored *Response, err error)
+	Complete(ctx context.Context, scope string, key string, response *Response) error
+	// Release gives up a claimed key whose request produced nothing to replay.
+	Release(ctx context.Context, scope string, key string) error
+	// Forget drops the keys claimed before before.
+	Forget(ctx context.Context, before time.Time) error
+}
+
+// Fingerprint identifies a request, so a key reused for another request can
+// be told from a retry.
+func Fingerprint(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ValidKey reports whether key is 1..MaxKeyLength printable ASCII characters.
+func ValidKey(key string) bool {
+	if key == "" || len(key) > MaxKeyLength {
+		return false
+	}
+	for _, r := range key {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package idempotency
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := Fingerprint("POST", "/api/forum/create", []byte(`{"slug":"pirates"}`))
+	if len(base) != 64 {
+		t.Fatalf("Fingerprint() = %q, want a hex SHA-256", base)
+	}
+	if again := Fingerprint("POST", "/api/forum/create", []byte(`{"slug":"pirates"}`)); again != base {
+		t.Errorf("Fingerprint() of the same request = %q, want %q", again, base)
+	}
+
+	others := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{"PUT", "/api/forum/create", `{"slug":"pirates"}`},
+		{"POST", "/api/user/pirates/create", `{"slug":"pirates"}`},
+		{"POST", "/api/forum/create", `{"slug":"sailors"}`},
+		{"POST", "/api/forum/create", ``},
+	}
+	for _, other := range others {
+		if got := Fingerprint(other.method, other.path, []byte(other.body)); got == base {
+			t.Errorf("Fingerprint(%q, %q, %q) equals the fingerprint of another request", other.method, other.path, other.body)
+		}
+	}
+}
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"8e03978e-40d5-43e8-bc93-6894a57f9324", true},
+		{"01ARZ3NDEKTSV4RRFFQ69G5FAV", true},
+		{"~ printable ASCII !", true},
+		{strings.Repeat("k", MaxKeyLength), true},
+		{"", false},
+		{strings.Repeat("k", MaxKeyLength+1), false},
+		{"tab\tinside", false},
+		{"line\nbreak", false},
+		{"ключ", false},
+		{"del\x7f", false},
+	}
+
+	for _, test := range tests {
+		if got := ValidKey(test.key); got != test.valid {
+			t.Errorf("ValidKey(%q) = %v, want %v", test.key, got, test.valid)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package permissions decides who may do what. It only knows about roles,
+// actions, forums and authors; looking the roles up and turning refusals into
+// API errors is left to the usecases, so the rules can be checked on their own.
+package permissions
+
+import (
+	"errors"
+	"strings"
+)
+
+// Role is what an actor is in the forum an action concerns. Every role
+// includes the ones below it: Admin > Owner > Moderator > Member.
+type Role string
+
+const (
+	Member    Role = "member"
+	Moderator Role = "moderator"
+	Owner     Role = "owner"
+	Admin     Role = "admin"
+)
+
+var rank = map[Role]int{Member: 0, Moderator: 1, Owner: 2, Admin: 3}
+
+// AtLeast reports whether r includes role. The zero Role is a Member.
+func (r Role) AtLeast(role Role) bool {
+	return rank[r] >= rank[role]
+}
+
+type Action string
+
+const (
+	EditProfile      Action = "edit_profile"
+	ChangePassword   Action = "change_password"
+	EditForum        Action = "edit_forum"
+	DeleteForum      Action = "delete_forum"
+	EditThread       Action = "edit_thread"
+	DeleteThread     Action = "delete_thread"
+	ArchiveThread    Action = "archive_thread"
+	EditPost         Action = "edit_post"
+	DeletePost       Action = "delete_post"
+	RestorePost      Action = "restore_post"
+	PurgePost        Action = "purge_post"
+	ManageModerators Action = "manage_moderators"
+	ManageAPIKeys    Action = "manage_api_keys"
+	Clear            Action = "clear"
+)
+
+// rule is who may perform an action: actors of at least role, the author of
+// the resource if author is set, and anonymous requests if anonymous is set
+// and the actor has AnonymousEdits. Actions marked session can't be performed
+// with an API key.
+type rule struct {
+	role      Role
+	author    bool
+	anonymous bool
+	session   bool
+}
+
+var rules = map[Action]rule{
+	EditProfile:      {role: Admin, author: true, anonymous: true},
+	ChangePassword:   {role: Admin, author: true, session: true},
+	EditForum:        {role: Owner},
+	DeleteForum:      {role: Owner, session: true},
+	EditThread:       {role: Moderator, author: true, anonymous: true},
+	DeleteThread:     {role: Moderator},
+	ArchiveThread:    {role: Moderator},
+	EditPost:         {role: Moderator, author: true, anonymous: true},
+	DeletePost:       {role: Moderator, author: true},
+	RestorePost:      {role: Moderator},
+	PurgePost:        {role: Moderator},
+	ManageModerators: {role: Owner, session: true},
+	ManageAPIKeys:    {role: Admin, author: true, session: true},
+	Clear:            {role: Admin, session: true},
+}
+
+var (
+	ErrUnauthenticated = errors.New("authentication required")
+	ErrForbidden       = errors.New("forbidden")
+	ErrSessionRequired = errors.New("not allowed with an API key")
+	ErrKeyForum        = errors.New("outside the forum of the API key")
+	ErrUnknownAction   = errors.New("unknown action")
+)
+
+// Actor is who performs an action. An empty Nickname is an anonymous request.
+type Actor struct {
+	Nickname string
+	Role     Role
+	// APIKey is set for requests authenticated with an API key, KeyForum
+	// for keys restricted to one forum.
+	APIKey   bool
+	KeyForum string
+	// AnonymousEdits lets anonymous actors edit profiles, threads and posts
+	// as the API allowed before authentication existed (auth.anonymous_edits).
+	AnonymousEdits bool
+}
+
+// WritesTo reports whether the API key of actor, if any, allows writing to forum.
+func (a Actor) WritesTo(forum string) bool {
+	return a.KeyForum == "" || strings.EqualFold(a.KeyForum, forum)
+}
+
+// Check returns nil if actor may perform action on a resource of forum
+// written by author. Either is "" when it doesn't apply.
+func Check(actor Actor, action Action, forum string, author string) error {
+	r, ok := rules[action]
+	switch {
+	case !ok:
+		return ErrUnknownAction
+	case actor.Nickname == "":
+		if r.anonymous && actor.AnonymousEdits {
+			return nil
+		}
+		return ErrUnauthenticated
+	case r.session && actor.APIKey:
+		return ErrSessionRequired
+	case !actor.WritesTo(forum):
+		return ErrKeyForum
+	case r.author && author != "" && strings.EqualFold(actor.Nickname, author):
+		return nil
+	case actor.Role.AtLeast(r.role):
+		return nil
+	default:
+		return ErrForbidden
+	}
+}
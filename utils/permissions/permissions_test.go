@@ -0,0 +1,92 @@
+package permissions
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRoleAtLeast(t *testing.T) {
+	order := []Role{"", Member, Moderator, Owner, Admin}
+	for i, role := range order {
+		for j, other := range order {
+			// The zero Role ranks as a Member.
+			want := max(i, 1) >= max(j, 1)
+			if got := role.AtLeast(other); got != want {
+				t.Errorf("%q.AtLeast(%q) = %v, want %v", role, other, got, want)
+			}
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	anonymous := Actor{}
+	legacy := Actor{AnonymousEdits: true}
+	member := Actor{Nickname: "alice", Role: Member}
+	moderator := Actor{Nickname: "bob", Role: Moderator}
+	admin := Actor{Nickname: "root", Role: Admin}
+	memberKey := Actor{Nickname: "alice", Role: Member, APIKey: true}
+	adminKey := Actor{Nickname: "root", Role: Admin, APIKey: true}
+	forumKey := Actor{Nickname: "alice", Role: Member, APIKey: true, KeyForum: "Pirates"}
+
+	tests := []struct {
+		name   string
+		actor  Actor
+		action Action
+		forum  string
+		author string
+		want   error
+	}{
+		{"unknown action", admin, Action("fly"), "", "", ErrUnknownAction},
+
+		{"anonymous may not edit profiles", anonymous, EditProfile, "", "alice", ErrUnauthenticated},
+		{"anonymous may not edit threads", anonymous, EditThread, "pirates", "alice", ErrUnauthenticated},
+		{"anonymous may not edit posts", anonymous, EditPost, "pirates", "alice", ErrUnauthenticated},
+		{"anonymous may not delete posts", anonymous, DeletePost, "pirates", "alice", ErrUnauthenticated},
+		{"legacy anonymous may edit profiles", legacy, EditProfile, "", "alice", nil},
+		{"legacy anonymous may edit threads", legacy, EditThread, "pirates", "alice", nil},
+		{"legacy anonymous may edit posts", legacy, EditPost, "pirates", "alice", nil},
+		{"legacy anonymous may not delete posts", legacy, DeletePost, "pirates", "alice", ErrUnauthenticated},
+		{"legacy anonymous may not change passwords", legacy, ChangePassword, "", "alice", ErrUnauthenticated},
+		{"anonymous may not change passwords", anonymous, ChangePassword, "", "alice", ErrUnauthenticated},
+		{"anonymous may not clear", anonymous, Clear, "", "", ErrUnauthenticated},
+
+		{"author edits own post", member, EditPost, "pirates", "alice", nil},
+		{"author matches case-insensitively", member, EditPost, "pirates", "ALICE", nil},
+		{"member may not edit others' posts", member, EditPost, "pirates", "carol", ErrForbidden},
+		{"member may not delete threads", member, DeleteThread, "pirates", "alice", ErrForbidden},
+		{"empty author is nobody's", member, DeletePost, "pirates", "", ErrForbidden},
+		{"moderator deletes others' posts", moderator, DeletePost, "pirates", "carol", nil},
+		{"moderator may not edit forums", moderator, EditForum, "pirates", "", ErrForbidden},
+		{"admin includes every role", admin, ManageModerators, "pirates", "", nil},
+
+		{"user changes own password", member, ChangePassword, "", "alice", nil},
+		{"user may not change others' passwords", member, ChangePassword, "", "carol", ErrForbidden},
+		{"admin resets passwords", admin, ChangePassword, "", "carol", nil},
+
+		{"API key may write posts", memberKey, EditPost, "pirates", "alice", nil},
+		{"session action refused to API keys", adminKey, Clear, "", "", ErrSessionRequired},
+		{"session check comes before author", memberKey, ChangePassword, "", "alice", ErrSessionRequired},
+		{"forum key writes its forum", forumKey, EditPost, "pirates", "alice", nil},
+		{"forum key refused elsewhere", forumKey, EditPost, "sailors", "alice", ErrKeyForum},
+		{"forum key check comes before author", forumKey, DeletePost, "sailors", "alice", ErrKeyForum},
+		{"forum key can't act outside forums", forumKey, EditProfile, "", "alice", ErrKeyForum},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Check(test.actor, test.action, test.forum, test.author); !errors.Is(got, test.want) {
+				t.Errorf("Check(%+v, %s, %q, %q) = %v, want %v", test.actor, test.action, test.forum, test.author, got, test.want)
+			}
+		})
+	}
+}
+
+func TestEveryActionHasARule(t *testing.T) {
+	actions := []Action{EditProfile, ChangePassword, EditForum, DeleteForum, EditThread, DeleteThread, ArchiveThread,
+		EditPost, DeletePost, RestorePost, PurgePost, ManageModerators, ManageAPIKeys, Clear}
+	for _, action := range actions {
+		if _, ok := rules[action]; !ok {
+			t.Errorf("action %s has no rule", action)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Memory keeps the buckets in the process, so every instance limits on its own.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func CreateMemory() *Memory {
+	return &Memory{buckets: make(map[string]*bucket)}
+}
+
+func (m *Memory) Take(ctx context.Context, key string, budget Budget, cost int) (tokens float64, allowed bool, err error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(budget.Burst)}
+		m.buckets[key] = b
+	} else {
+		b.tokens = math.Min(float64(budget.Burst), b.tokens+now.Sub(b.updated).Seconds()*budget.Rate)
+	}
+	b.updated = now
+
+	if b.tokens >= float64(cost) {
+		b.tokens -= float64(cost)
+		allowed = true
+	}
+	return b.tokens, allowed, nil
+}
+
+func (m *Memory) Forget(ctx context.Context, before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, b := range m.buckets {
+		if b.updated.Before(before) {
+			delete(m.buckets, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package ratelimit throttles clients with token buckets. Every client has a
+// bucket per budget (e.g. reads, writes, votes) that refills at the budget
+// rate up to its burst; a request takes tokens from it or is refused. The
+// buckets live in a Store: Memory for a single instance, or a shared one
+// such as the Postgres store of the repositories for several instances.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// Budget is Rate tokens per second, at most Burst of them saved up.
+type Budget struct {
+	Rate  float64
+	Burst int
+}
+
+// fill is how long an empty bucket takes to fill up.
+func (b Budget) fill() time.Duration {
+	return seconds(float64(b.Burst) / b.Rate)
+}
+
+type Store interface {
+	// Take refills the bucket key, creating it full, and removes cost tokens
+	// from it if it holds that many. It returns the tokens left.
+	Take(ctx context.Context, key string, budget Budget, cost int) (tokens float64, allowed bool, err error)
+	// Forget drops the buckets last taken from before before.
+	Forget(ctx context.Context, before time.Time) error
+}
+
+// Result describes a bucket after a request, in the terms of the RateLimit
+// headers: Limit is the burst, Reset the time until the bucket is full again
+// and RetryAfter, for refused requests, the time until it holds enough tokens.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+var ErrUnknownBudget = errors.New("unknown rate limit budget")
+
+const sweepInterval = time.Minute
+
+type Limiter struct {
+	store   Store
+	budgets map[string]Budget
+	// idle is how long after its last use every bucket is full and can be forgotten.
+	idle      time.Duration
+	lastSweep atomic.Int64
+}
+
+func CreateLimiter(store Store, budgets map[string]Budget) *Limiter {
+	limiter := &Limiter{store: store, budgets: budgets}
+	for _, budget := range budgets {
+		if fill := budget.fill(); fill > limiter.idle {
+			limiter.idle = fill
+		}
+	}
+	limiter.lastSweep.Store(time.Now().UnixNano())
+	return limiter
+}
+
+// Request takes the tokens of a single request from the bucket of a client,
+// possibly in several steps.
+type Request struct {
+	limiter *Limiter
+	budget  string
+	client  string
+	taken   int
+}
+
+func (l *Limiter) Request(budgetName string, client string) *Request {
+	return &Request{limiter: l, budget: budgetName, client: client}
+}
+
+// Take takes cost more tokens for the request, at least one on its first
+// step. The steps of a request take at most the burst in total, as the bucket
+// never holds more: a batch above the burst waits for a full bucket instead
+// of being refused forever.
+func (r *Request) Take(ctx context.Context, cost int) (result Result, err error) {
+	budget, ok := r.limiter.budgets[r.budget]
+	if !ok {
+		return Result{}, ErrUnknownBudget
+	}
+	cost = min(max(cost, 1), budget.Burst-r.taken)
+
+	r.limiter.sweep()
+	tokens, allowed, err := r.limiter.store.Take(ctx, r.budget+":"+r.client, budget, cost)
+	if err != nil {
+		return Result{}, err
+	}
+	if allowed {
+		r.taken += cost
+	}
+
+	result = Result{
+		Allowed:   allowed,
+		Limit:     budget.Burst,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(budget.Burst) - tokens) / budget.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((float64(cost) - tokens) / budget.Rate)
+	}
+	return result, nil
+}
+
+// sweep forgets idle buckets in the background, at most once per sweepInterval.
+func (l *Limiter) sweep() {
+	last := l.lastSweep.Load()
+	now := time.Now()
+	if now.Sub(time.Unix(0, last)) < sweepInterval || !l.lastSweep.CompareAndSwap(last, now.UnixNano()) {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sweepInterval)
+		defer cancel()
+		_ = l.store.Forget(ctx, now.Add(-l.idle))
+	}()
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+type chargerKey struct{}
+
+// Charger takes extra tokens for a request whose full cost is only known
+// once its body is read, e.g. the number of posts in a batch.
+type Charger func(ctx context.Context, cost int) error
+
+func WithCharger(ctx context.Context, charger Charger) context.Context {
+	return context.WithValue(ctx, chargerKey{}, charger)
+}
+
+// Charge takes cost more tokens for the request of ctx. It does nothing when
+// the request isn't rate limited or cost isn't positive.
+func Charge(ctx context.Context, cost int) error {
+	charger, _ := ctx.Value(chargerKey{}).(Charger)
+	if charger == nil || cost <= 0 {
+		return nil
+	}
+	return charger(ctx, cost)
+}
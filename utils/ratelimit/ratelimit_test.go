@@ -0,0 +1,182 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryTake(t *testing.T) {
+	ctx := context.Background()
+	memory := CreateMemory()
+	budget := Budget{Rate: 2, Burst: 5}
+
+	tokens, allowed, _ := memory.Take(ctx, "a", budget, 3)
+	if !allowed || tokens != 2 {
+		t.Fatalf("first Take() = %v, %v, want 2, true as new buckets start full", tokens, allowed)
+	}
+	tokens, allowed, _ = memory.Take(ctx, "a", budget, 3)
+	if allowed || tokens < 2 || tokens > 2.01 {
+		t.Fatalf("second Take() = %v, %v, want about 2, false", tokens, allowed)
+	}
+	if _, allowed, _ = memory.Take(ctx, "b", budget, 5); !allowed {
+		t.Fatal("Take() from another bucket was refused")
+	}
+
+	// A second at 2 tokens per second adds 2 tokens.
+	memory.buckets["a"].updated = memory.buckets["a"].updated.Add(-time.Second)
+	tokens, allowed, _ = memory.Take(ctx, "a", budget, 3)
+	if !allowed || tokens < 1 || tokens > 1.01 {
+		t.Fatalf("Take() after a second = %v, %v, want about 1, true", tokens, allowed)
+	}
+
+	// Refilling stops at the burst.
+	memory.buckets["a"].updated = memory.buckets["a"].updated.Add(-time.Hour)
+	if tokens, _, _ = memory.Take(ctx, "a", budget, 1); tokens != 4 {
+		t.Fatalf("Take() after an hour = %v, want 4", tokens)
+	}
+}
+
+func TestMemoryForget(t *testing.T) {
+	ctx := context.Background()
+	memory := CreateMemory()
+	budget := Budget{Rate: 1, Burst: 1}
+
+	_, _, _ = memory.Take(ctx, "old", budget, 1)
+	_, _, _ = memory.Take(ctx, "new", budget, 1)
+	memory.buckets["old"].updated = time.Now().Add(-time.Hour)
+
+	if err := memory.Forget(ctx, time.Now().Add(-time.Minute)); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	if _, ok := memory.buckets["old"]; ok {
+		t.Error("Forget() kept an idle bucket")
+	}
+	if _, ok := memory.buckets["new"]; !ok {
+		t.Error("Forget() dropped a bucket in use")
+	}
+}
+
+// fixedStore leaves tokens in every bucket and records what was taken.
+type fixedStore struct {
+	tokens float64
+	key    string
+	cost   int
+}
+
+func (s *fixedStore) Take(ctx context.Context, key string, budget Budget, cost int) (float64, bool, error) {
+	s.key, s.cost = key, cost
+	if s.tokens >= float64(cost) {
+		return s.tokens - float64(cost), true, nil
+	}
+	return s.tokens, false, nil
+}
+
+func (s *fixedStore) Forget(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func TestLimiterTake(t *testing.T) {
+	budgets := map[string]Budget{"write": {Rate: 2, Burst: 10}}
+
+	tests := []struct {
+		name   string
+		tokens float64
+		cost   int
+		want   Result
+		taken  int
+	}{
+		{
+			name:   "allowed",
+			tokens: 10,
+			cost:   3,
+			taken:  3,
+			want:   Result{Allowed: true, Limit: 10, Remaining: 7, Reset: 1500 * time.Millisecond},
+		},
+		{
+			name:   "fractional tokens round down",
+			tokens: 4.5,
+			cost:   1,
+			taken:  1,
+			want:   Result{Allowed: true, Limit: 10, Remaining: 3, Reset: 3250 * time.Millisecond},
+		},
+		{
+			name:   "refused",
+			tokens: 1,
+			cost:   4,
+			taken:  4,
+			want:   Result{Limit: 10, Remaining: 1, Reset: 4500 * time.Millisecond, RetryAfter: 1500 * time.Millisecond},
+		},
+		{
+			name:   "cost is at least one",
+			tokens: 10,
+			cost:   0,
+			taken:  1,
+			want:   Result{Allowed: true, Limit: 10, Remaining: 9, Reset: 500 * time.Millisecond},
+		},
+		{
+			name:   "cost is at most the burst",
+			tokens: 10,
+			cost:   50,
+			taken:  10,
+			want:   Result{Allowed: true, Limit: 10, Remaining: 0, Reset: 5 * time.Second},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			store := &fixedStore{tokens: test.tokens}
+			result, err := CreateLimiter(store, budgets).Request("write", "ip:1.2.3.4").Take(context.Background(), test.cost)
+			if err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if result != test.want {
+				t.Errorf("Take() = %+v, want %+v", result, test.want)
+			}
+			if store.key != "write:ip:1.2.3.4" || store.cost != test.taken {
+				t.Errorf("store took %d from %q, want %d from %q", store.cost, store.key, test.taken, "write:ip:1.2.3.4")
+			}
+		})
+	}
+}
+
+func TestLimiterUnknownBudget(t *testing.T) {
+	limiter := CreateLimiter(&fixedStore{}, map[string]Budget{"read": {Rate: 1, Burst: 1}})
+	if _, err := limiter.Request("vote", "ip:1.2.3.4").Take(context.Background(), 1); !errors.Is(err, ErrUnknownBudget) {
+		t.Errorf("Take() error = %v, want %v", err, ErrUnknownBudget)
+	}
+}
+
+func TestLimiterIdle(t *testing.T) {
+	limiter := CreateLimiter(&fixedStore{}, map[string]Budget{"read": {Rate: 100, Burst: 200}, "vote": {Rate: 1, Burst: 10}})
+	if limiter.idle != 10*time.Second {
+		t.Errorf("idle = %v, want the longest fill time 10s", limiter.idle)
+	}
+}
+
+func TestRequestTakeBatch(t *testing.T) {
+	ctx := context.Background()
+	limiter := CreateLimiter(CreateMemory(), map[string]Budget{"write": {Rate: 1, Burst: 10}})
+
+	// A batch of 50 posts: one token for the request, then one per other post.
+	request := limiter.Request("write", "ip:1.2.3.4")
+	if result, _ := request.Take(ctx, 1); !result.Allowed {
+		t.Fatal("first Take() of the request was refused on a full bucket")
+	}
+	result, err := request.Take(ctx, 49)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Take() of an oversized batch = %+v, want allowed emptying the bucket", result)
+	}
+	if result, _ = request.Take(ctx, 5); !result.Allowed {
+		t.Error("Take() past the burst was refused, want it free once the request took the burst")
+	}
+
+	next := limiter.Request("write", "ip:1.2.3.4")
+	if result, _ = next.Take(ctx, 1); result.Allowed || result.RetryAfter <= 0 {
+		t.Errorf("Take() of the next request = %+v, want refused with a Retry-After", result)
+	}
+}
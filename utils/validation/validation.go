@@ -0,0 +1,126 @@
+// Package validation checks request models against the rules declared in
+// their `validate` struct tags and reports every violated rule at once.
+// Besides the built-in rules of go-playground/validator it knows "slug" and
+// "nickname".
+package validation
+
+import (
+	"db_project/utils/errors"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	slugReg     = regexp.MustCompile(`^(\d|\w|-|_)*(\w|-|_)(\d|\w|-|_)*$`)
+	nicknameReg = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+)
+
+// validate is safe for concurrent use and caches the rules of every type.
+var validate = create()
+
+func create() *validator.Validate {
+	v := validator.New()
+	v.SetTagName("validate")
+	// Fields are reported under their JSON or query parameter names.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		for _, tag := range []string{"json", "form"} {
+			if name := strings.Split(field.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+				return name
+			}
+		}
+		return field.Name
+	})
+	_ = v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugReg.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("nickname", func(fl validator.FieldLevel) bool {
+		return nicknameReg.MatchString(fl.Field().String())
+	})
+	return v
+}
+
+// Struct checks every field of s, or of every element if s is a slice.
+func Struct(s interface{}) error {
+	if reflect.Indirect(reflect.ValueOf(s)).Kind() == reflect.Slice {
+		return result(validate.Var(s, "dive"))
+	}
+	return result(validate.Struct(s))
+}
+
+// Partial checks only the fields of s that are set, for updates where an
+// empty field keeps the stored value.
+func Partial(s interface{}) error {
+	value := reflect.Indirect(reflect.ValueOf(s))
+	var fields []string
+	for i := 0; i < value.NumField(); i++ {
+		if !value.Field(i).IsZero() {
+			fields = append(fields, value.Type().Field(i).Name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return result(validate.StructPartial(s, fields...))
+}
+
+// Slug checks a slug taken from the path.
+func Slug(field string, slug string) error {
+	if slugReg.MatchString(slug) {
+		return nil
+	}
+	return errors.ValidationFailed.WithFields([]errors.FieldError{{Field: field, Rule: "slug"}})
+}
+
+// Nickname checks a nickname taken from the path.
+func Nickname(field string, nickname string) error {
+	if nicknameReg.MatchString(nickname) {
+		return nil
+	}
+	return errors.ValidationFailed.WithFields([]errors.FieldError{{Field: field, Rule: "nickname"}})
+}
+
+// SlugOrID tells a numeric thread ID from a thread slug.
+func SlugOrID(field string, slugOrID string) (slug string, id int, err error) {
+	if id, err = strconv.Atoi(slugOrID); err == nil {
+		return
+	}
+	if err = Slug(field, slugOrID); err != nil {
+		return
+	}
+	slug = slugOrID
+	return
+}
+
+func result(err error) error {
+	if err == nil {
+		return nil
+	}
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return err
+	}
+
+	fields := make([]errors.FieldError, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		fields = append(fields, errors.FieldError{
+			Field: fieldPath(fieldErr.Namespace()),
+			Rule:  fieldErr.Tag(),
+			Param: fieldErr.Param(),
+		})
+	}
+	return errors.ValidationFailed.WithFields(fields)
+}
+
+// fieldPath drops the struct name from "Thread.title", slices give "[1].message".
+func fieldPath(namespace string) string {
+	if strings.HasPrefix(namespace, "[") {
+		return namespace
+	}
+	if i := strings.IndexByte(namespace, '.'); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -0,0 +1,148 @@
+package validation
+
+import (
+	"db_project/utils/errors"
+	"reflect"
+	"testing"
+)
+
+type testUser struct {
+	Nickname string `json:"nickname" validate:"required,nickname,max=8"`
+	Email    string `json:"email" validate:"required,email"`
+	About    string `json:"about" validate:"max=4"`
+}
+
+type testQuery struct {
+	Limit int    `form:"limit" validate:"min=1,max=10"`
+	Sort  string `form:"sort" validate:"oneof=flat tree"`
+}
+
+type testForumUpdate struct {
+	Title       string  `json:"title" validate:"required,max=8"`
+	Description *string `json:"description" validate:"omitempty,max=4"`
+}
+
+type testPost struct {
+	Message string `json:"message" validate:"required"`
+}
+
+func fieldsOf(t *testing.T, err error) []errors.FieldError {
+	t.Helper()
+	var appErr *errors.Error
+	if !errors.As(err, &appErr) || !errors.Is(err, errors.ValidationFailed) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	return appErr.Fields()
+}
+
+func TestStruct(t *testing.T) {
+	if err := Struct(&testUser{Nickname: "j.sparrow", Email: "jack@pearl.sea"}); err == nil {
+		t.Error("Struct() accepted a nickname over max")
+	}
+	if err := Struct(&testUser{Nickname: "jack_1", Email: "jack@pearl.sea", About: "hi"}); err != nil {
+		t.Errorf("Struct() error = %v, want nil", err)
+	}
+
+	fields := fieldsOf(t, Struct(&testUser{Nickname: "jack sparrow", About: "pirate"}))
+	want := []errors.FieldError{
+		{Field: "nickname", Rule: "nickname"},
+		{Field: "email", Rule: "required"},
+		{Field: "about", Rule: "max", Param: "4"},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+}
+
+func TestStructNamesQueryParameters(t *testing.T) {
+	fields := fieldsOf(t, Struct(&testQuery{Limit: 0, Sort: "random"}))
+	want := []errors.FieldError{
+		{Field: "limit", Rule: "min", Param: "1"},
+		{Field: "sort", Rule: "oneof", Param: "flat tree"},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+}
+
+func TestStructSlice(t *testing.T) {
+	posts := []*testPost{{Message: "first"}, {}}
+	fields := fieldsOf(t, Struct(posts))
+	want := []errors.FieldError{{Field: "[1].message", Rule: "required"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+}
+
+func TestPartial(t *testing.T) {
+	if err := Partial(&testUser{}); err != nil {
+		t.Errorf("Partial() of an empty update error = %v, want nil", err)
+	}
+	if err := Partial(&testUser{About: "hi"}); err != nil {
+		t.Errorf("Partial() error = %v, want nil as empty fields keep their values", err)
+	}
+
+	fields := fieldsOf(t, Partial(&testUser{Email: "not an email"}))
+	want := []errors.FieldError{{Field: "email", Rule: "email"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+}
+
+func TestPartialPointer(t *testing.T) {
+	empty, long := "", "too long"
+	if err := Partial(&testForumUpdate{Description: &empty}); err != nil {
+		t.Errorf("Partial() of an emptied field error = %v, want nil", err)
+	}
+
+	fields := fieldsOf(t, Partial(&testForumUpdate{Description: &long}))
+	want := []errors.FieldError{{Field: "description", Rule: "max", Param: "4"}}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+}
+
+func TestPathParameters(t *testing.T) {
+	tests := []struct {
+		value    string
+		slug     bool
+		nickname bool
+	}{
+		{"pirates", true, true},
+		{"black-pearl", true, false},
+		{"j.sparrow", false, true},
+		{"42", true, true},
+		{"with space", false, false},
+		{"", false, false},
+	}
+
+	for _, test := range tests {
+		if err := Slug("slug", test.value); (err == nil) != test.slug {
+			t.Errorf("Slug(%q) error = %v, want valid %v", test.value, err, test.slug)
+		}
+		if err := Nickname("nickname", test.value); (err == nil) != test.nickname {
+			t.Errorf("Nickname(%q) error = %v, want valid %v", test.value, err, test.nickname)
+		}
+	}
+}
+
+func TestSlugOrID(t *testing.T) {
+	tests := []struct {
+		value   string
+		slug    string
+		id      int
+		invalid bool
+	}{
+		{value: "42", id: 42},
+		{value: "black-pearl", slug: "black-pearl"},
+		{value: "no way", invalid: true},
+	}
+
+	for _, test := range tests {
+		slug, id, err := SlugOrID("slug_or_id", test.value)
+		if (err != nil) != test.invalid || slug != test.slug || id != test.id {
+			t.Errorf("SlugOrID(%q) = %q, %d, %v, want %q, %d, invalid %v",
+				test.value, slug, id, err, test.slug, test.id, test.invalid)
+		}
+	}
+}